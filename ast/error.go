@@ -0,0 +1,140 @@
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	//Source represents template source
+	Source struct {
+		Name  string
+		Input []byte
+	}
+
+	//Error represents template error with the source location
+	Error struct {
+		Template  string
+		Line      int
+		Column    int
+		Offset    int
+		Directive string
+		Snippet   string
+		Err       error
+	}
+)
+
+func (e *Error) Error() string {
+	location := fmt.Sprintf("%v:%v", e.Line, e.Column)
+	if e.Template != "" {
+		location = e.Template + ":" + location
+	}
+
+	if e.Directive == "" {
+		return fmt.Sprintf("%v: %v\n%v", location, e.Err, e.Snippet)
+	}
+	return fmt.Sprintf("%v: %v, directive: %v\n%v", location, e.Err, e.Directive, e.Snippet)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+//NewError creates Error for the given source position, err is returned as is if it is already an Error
+func (s *Source) NewError(pos *Pos, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var located *Error
+	if errors.As(err, &located) {
+		return err
+	}
+
+	return s.Locate(pos, "").WithError(err)
+}
+
+//Locate creates Error without a cause for the given statement position, used to report runtime errors.
+//If expression is found within the statement, the expression location is used instead.
+func (s *Source) Locate(pos *Pos, expression string) *Error {
+	var input []byte
+	result := &Error{Line: 1, Column: 1}
+	if s != nil {
+		input = s.Input
+		result.Template = s.Name
+	}
+
+	offset, end := 0, 0
+	if pos != nil {
+		offset, end = pos.Offset, pos.Offset+pos.Length
+	}
+
+	if end > len(input) {
+		end = len(input)
+	}
+
+	if offset > end {
+		offset = end
+	}
+
+	if expression != "" {
+		if index := bytes.Index(input[offset:end], []byte(expression)); index != -1 {
+			offset += index
+			end = offset + len(expression)
+		}
+	}
+
+	lineStart := 0
+	for i := 0; i < offset; i++ {
+		if input[i] == '\n' {
+			result.Line++
+			lineStart = i + 1
+		}
+	}
+
+	lineEnd := len(input)
+	if index := bytes.IndexByte(input[lineStart:], '\n'); index != -1 {
+		lineEnd = lineStart + index
+	}
+
+	if end > lineEnd {
+		end = lineEnd
+	}
+
+	result.Offset = offset
+	result.Column = utf8.RuneCount(input[lineStart:offset]) + 1
+	result.Directive = string(input[offset:end])
+	if expression != "" {
+		result.Directive = expression
+	}
+
+	line := string(input[lineStart:lineEnd])
+	result.Snippet = line + "\n" + caretIndent(input[lineStart:offset]) + "^"
+	return result
+}
+
+//WithError returns copy of the location with the given cause
+func (e *Error) WithError(err error) error {
+	if e == nil {
+		return err
+	}
+
+	result := *e
+	result.Err = err
+	return &result
+}
+
+func caretIndent(prefix []byte) string {
+	builder := strings.Builder{}
+	for _, r := range string(prefix) {
+		if r == '\t' {
+			builder.WriteByte('\t')
+			continue
+		}
+		builder.WriteByte(' ')
+	}
+	return builder.String()
+}
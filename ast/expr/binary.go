@@ -14,7 +14,7 @@ type Binary struct {
 
 func (b *Binary) Type() reflect.Type {
 	switch b.Token {
-	case ast2.LEQ, ast2.LSS, ast2.GTR, ast2.GTE, ast2.NEQ, ast2.EQ:
+	case ast2.LEQ, ast2.LSS, ast2.GTR, ast2.GTE, ast2.NEQ, ast2.EQ, ast2.AND, ast2.OR:
 		return reflect.TypeOf(true)
 	}
 
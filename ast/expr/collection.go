@@ -0,0 +1,27 @@
+package expr
+
+import (
+	"github.com/viant/velty/ast"
+	"reflect"
+)
+
+//List represents list literal i.e. [1, 2, $x]
+type List struct {
+	Elements []ast.Expression
+}
+
+//Type returns nil, element type is inferred while planning
+func (l *List) Type() reflect.Type {
+	return nil
+}
+
+//Map represents map literal i.e. {"a": 1, "b": $x}
+type Map struct {
+	Keys   []ast.Expression
+	Values []ast.Expression
+}
+
+//Type returns nil, key and value types are inferred while planning
+func (m *Map) Type() reflect.Type {
+	return nil
+}
@@ -1,17 +1,17 @@
 package expr
 
 import (
+	"github.com/viant/velty/ast"
 	"reflect"
 )
 
+//Range represents integer range i.e. [1..$count], [10..1] or [0...10]
 type Range struct {
-	X *Literal
-	Y *Literal
+	X         ast.Expression
+	Y         ast.Expression
+	Inclusive bool //Velocity [a..b] range includes both ends, legacy [a...b] excludes the last one
 }
 
 func (r *Range) Type() reflect.Type {
-	if r.X.Type() != nil {
-		return r.X.Type()
-	}
-	return r.Y.Type()
+	return reflect.TypeOf(0)
 }
@@ -7,9 +7,13 @@ import (
 
 //Select represents dynamic variable
 type Select struct {
+	ast.Pos
 	ID       string
 	X        ast.Expression
 	FullName string
+	Quiet    bool           //quiet reference i.e. $!foo renders empty if unresolved
+	NullSafe bool           //null-safe navigation i.e. $order?.Customer
+	Default  ast.Expression //alternate value i.e. ${name|'anonymous'}
 }
 
 func (s Select) Type() reflect.Type {
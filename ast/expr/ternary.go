@@ -0,0 +1,20 @@
+package expr
+
+import (
+	"github.com/viant/velty/ast"
+	"reflect"
+)
+
+//Ternary represents conditional expression i.e. $cond ? "yes" : "no"
+type Ternary struct {
+	Cond ast.Expression
+	X    ast.Expression
+	Y    ast.Expression
+}
+
+func (t *Ternary) Type() reflect.Type {
+	if rType := t.X.Type(); rType != nil {
+		return rType
+	}
+	return t.Y.Type()
+}
@@ -0,0 +1,32 @@
+package ast
+
+//Pos represents statement position in the template source
+type Pos struct {
+	Offset int
+	Length int
+}
+
+//Position returns statement position
+func (p *Pos) Position() *Pos {
+	return p
+}
+
+//Locatable represents statement with the template source position
+type Locatable interface {
+	Position() *Pos
+}
+
+//Location represents expression location, resolved into Error only when runtime error occurs
+type Location struct {
+	Source     *Source
+	Pos        *Pos
+	Expression string
+}
+
+//WithError returns Error with the location and the given cause
+func (l *Location) WithError(err error) error {
+	if l == nil || err == nil {
+		return err
+	}
+	return l.Source.Locate(l.Pos, l.Expression).WithError(err)
+}
@@ -6,6 +6,7 @@ import (
 
 //Statement represents assign statement i.e. $var = 10
 type Statement struct {
+	ast2.Pos
 	X  ast2.Expression //left operand
 	Op ast2.Operand    // =
 	Y  ast2.Expression //right operand
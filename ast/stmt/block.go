@@ -16,3 +16,18 @@ func (b *Block) Statements() []ast.Statement {
 func (b *Block) AddStatement(statement ast.Statement) {
 	b.Stmt = append(b.Stmt, statement)
 }
+
+//NamedBlock represents layout section overridable by the extending template i.e. #block("content") ... #end
+type NamedBlock struct {
+	ast.Pos
+	Name string
+	Body Block
+}
+
+func (b *NamedBlock) Statements() []ast.Statement {
+	return b.Body.Statements()
+}
+
+func (b *NamedBlock) AddStatement(statement ast.Statement) {
+	b.Body.AddStatement(statement)
+}
@@ -0,0 +1,20 @@
+package stmt
+
+import (
+	"github.com/viant/velty/ast"
+)
+
+//Break represents #break, terminating the innermost loop or macro
+type Break struct {
+	ast.Pos
+}
+
+//Continue represents #continue, skipping to the next iteration of the innermost loop
+type Continue struct {
+	ast.Pos
+}
+
+//Stop represents #stop, halting rendering while keeping output produced so far
+type Stop struct {
+	ast.Pos
+}
@@ -6,5 +6,6 @@ import (
 
 //Evaluate represents template expression evaluated in runtime
 type Evaluate struct {
+	ast.Pos
 	X ast.Expression
 }
@@ -7,6 +7,7 @@ import (
 
 //If represents conditional statement
 type If struct {
+	ast2.Pos
 	Condition ast2.Expression
 	Body      Block
 	Else      *If
@@ -0,0 +1,44 @@
+package stmt
+
+import (
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+)
+
+//Macro represents macro definition i.e. #macro(name $a $b) ... #end
+type Macro struct {
+	ast.Pos
+	Name   string
+	Params []*expr.Select
+	Body   Block
+}
+
+func (m *Macro) Statements() []ast.Statement {
+	return m.Body.Statements()
+}
+
+func (m *Macro) AddStatement(statement ast.Statement) {
+	m.Body.AddStatement(statement)
+}
+
+//MacroCall represents macro invocation i.e. #name($x $y)
+type MacroCall struct {
+	ast.Pos
+	Name string
+	Args []ast.Expression
+	Raw  string
+	Body *Block
+}
+
+//BlockMacroCall represents block macro invocation i.e. #@name($x) ... #end
+type BlockMacroCall struct {
+	MacroCall
+}
+
+func (c *BlockMacroCall) Statements() []ast.Statement {
+	return c.Body.Statements()
+}
+
+func (c *BlockMacroCall) AddStatement(statement ast.Statement) {
+	c.Body.AddStatement(statement)
+}
@@ -0,0 +1,11 @@
+package stmt
+
+import "github.com/viant/velty/ast"
+
+//Param represents template input declaration i.e. #param($limit int), #var($title string)
+type Param struct {
+	ast.Pos
+	Name     string
+	Type     string
+	Required bool //declared with #param, #var declares optional input
+}
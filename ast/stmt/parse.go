@@ -0,0 +1,23 @@
+package stmt
+
+import (
+	"github.com/viant/velty/ast"
+)
+
+//Parse represents template loaded, compiled and executed within the current state i.e. #parse("header.vm")
+type Parse struct {
+	ast.Pos
+	X ast.Expression
+}
+
+//Include represents resource loaded and appended without processing i.e. #include("static.txt")
+type Include struct {
+	ast.Pos
+	X ast.Expression
+}
+
+//Extends represents layout template inherited by the current template i.e. #extends("layout.vm")
+type Extends struct {
+	ast.Pos
+	X ast.Expression
+}
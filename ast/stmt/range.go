@@ -1,12 +1,14 @@
 package stmt
 
 import (
+	"fmt"
 	ast2 "github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/expr"
 )
 
 //ForLoop represents regular for loop
 type ForLoop struct {
+	ast2.Pos
 	Init ast2.Statement
 	Cond ast2.Expression
 	Body Block
@@ -23,10 +25,12 @@ func (r *ForLoop) AddStatement(statement ast2.Statement) {
 
 //ForEach represents for each loop
 type ForEach struct {
+	ast2.Pos
 	Index *expr.Select
 	Item  *expr.Select
 	Set   ast2.Expression
 	Body  Block
+	Else  *Block
 }
 
 func (f *ForEach) Statements() []ast2.Statement {
@@ -34,5 +38,18 @@ func (f *ForEach) Statements() []ast2.Statement {
 }
 
 func (f *ForEach) AddStatement(statement ast2.Statement) {
+	if f.Else != nil {
+		f.Else.AddStatement(statement)
+		return
+	}
 	f.Body.AddStatement(statement)
 }
+
+//AddElse starts #else branch, rendered when the collection is empty
+func (f *ForEach) AddElse() error {
+	if f.Else != nil {
+		return fmt.Errorf("unexpected #else, foreach already has #else branch")
+	}
+	f.Else = &Block{}
+	return nil
+}
@@ -7,6 +7,7 @@ const (
 	SUB    = Token('-')
 	MUL    = Token('*')
 	QUO    = Token('/')
+	MOD    = Token('%')
 	GTR    = Token('>')
 	GTE    = Token(">=")
 	LSS    = Token("<")
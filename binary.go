@@ -1,25 +1,57 @@
 package velty
 
 import (
+	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/expr"
 	eexpr "github.com/viant/velty/est/expr"
 	"github.com/viant/velty/est/op"
 	types "github.com/viant/xunsafe/converter"
 	"reflect"
+	"strings"
 )
 
 func (p *Planner) compileBinary(actual *expr.Binary) (*op.Expression, error) {
-	x, err := p.compileExpr(actual.X)
+	compile := p.compileExpr
+	if actual.Token == ast.AND || actual.Token == ast.OR {
+		compile = p.compileCondition
+	}
+
+	x, err := compile(actual.X)
 	if err != nil {
 		return nil, err
 	}
 
-	y, err := p.compileExpr(actual.Y)
+	y, err := compile(actual.Y)
 	if err != nil {
 		return nil, err
 	}
 
-	unify, err := types.NormalizeAndUnify(x.Type, y.Type)
+	if actual.Token == ast.ADD {
+		if x, err = p.unresolvedAsString(actual.X, x, y); err != nil {
+			return nil, err
+		}
+
+		if y, err = p.unresolvedAsString(actual.Y, y, x); err != nil {
+			return nil, err
+		}
+	}
+
+	if x, err = p.unsignedLiteral(actual.X, x, y); err != nil {
+		return nil, err
+	}
+
+	if y, err = p.unsignedLiteral(actual.Y, y, x); err != nil {
+		return nil, err
+	}
+
+	unify, numeric, err := eexpr.Widen(actual.Token, x.Type, y.Type)
+	if err == nil && !numeric {
+		var isTime bool
+		if unify, isTime = eexpr.UnifyTime(x.Type, y.Type); !isTime {
+			unify, err = types.NormalizeAndUnify(x.Type, y.Type)
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +60,13 @@ func (p *Planner) compileBinary(actual *expr.Binary) (*op.Expression, error) {
 	y.Unify = unify.Y
 
 	resultType := notNilType(types.NormalizeType(actual.Type()), unify.RType)
+	if numeric && resultType.Kind() != reflect.Bool {
+		resultType = unify.RType
+	}
 	acc := p.accumulator(resultType)
+	if actual.Token == ast.QUO || actual.Token == ast.MOD {
+		acc.Location = p.location("")
+	}
 	resultExpr := &op.Expression{Selector: acc, Type: acc.Type}
 
 	computeNew, err := eexpr.Binary(actual.Token, x, y, resultExpr)
@@ -42,6 +80,29 @@ func (p *Planner) compileBinary(actual *expr.Binary) (*op.Expression, error) {
 	}, nil
 }
 
+//unresolvedAsString replaces unresolved reference concatenated with a string with its rendered form, i.e. "Hello $name"
+func (p *Planner) unresolvedAsString(node ast.Expression, expression, other *op.Expression) (*op.Expression, error) {
+	selector, ok := node.(*expr.Select)
+	if !ok || expression.Type != nil || other.Type == nil || other.Type.Kind() != reflect.String {
+		return expression, nil
+	}
+
+	text := selector.FullName
+	if selector.Quiet || p.referenceMode == EmptyReferences {
+		text = ""
+	}
+	return p.literalExpr(expr.StringLiteral(text))
+}
+
+//unsignedLiteral types non-negative integer literal used with unsigned operand as uint64, i.e. $total / 2
+func (p *Planner) unsignedLiteral(node ast.Expression, expression, other *op.Expression) (*op.Expression, error) {
+	literal, ok := node.(*expr.Literal)
+	if !ok || literal.RType.Kind() != reflect.Int || strings.HasPrefix(literal.Value, "-") || !eexpr.IsUnsigned(other.Type) {
+		return expression, nil
+	}
+	return p.literalExpr(&expr.Literal{Value: literal.Value, RType: reflect.TypeOf(uint64(0))})
+}
+
 func notNilType(types ...reflect.Type) reflect.Type {
 	for _, rType := range types {
 		if rType != nil {
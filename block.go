@@ -1,6 +1,7 @@
 package velty
 
 import (
+	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/stmt"
 	"github.com/viant/velty/est"
 	estmt "github.com/viant/velty/est/stmt"
@@ -9,10 +10,31 @@ import (
 func (p *Planner) compileBlock(root *stmt.Block) (est.New, error) {
 	var newComputers = make([]est.New, len(root.Stmt))
 	var err error
+	p.registerMacros(root)
+	pos := p.pos
+	defer func() { p.pos = pos }()
 	for i, item := range root.Stmt {
+		if locatable, ok := item.(ast.Locatable); ok {
+			p.pos = locatable.Position()
+		}
+
 		if newComputers[i], err = p.compileStmt(item); err != nil {
-			return nil, err
+			return nil, p.locate(item, err)
 		}
 	}
 	return estmt.NewBlock(newComputers), nil
 }
+
+//locate adds statement position in the current template to the error
+func (p *Planner) locate(statement ast.Statement, err error) error {
+	var pos *ast.Pos
+	if locatable, ok := statement.(ast.Locatable); ok {
+		pos = locatable.Position()
+	}
+	return p.source.NewError(pos, err)
+}
+
+//location returns current statement location used to report runtime errors
+func (p *Planner) location(expression string) *ast.Location {
+	return &ast.Location{Source: p.source, Pos: p.pos, Expression: expression}
+}
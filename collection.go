@@ -0,0 +1,83 @@
+package velty
+
+import (
+	"fmt"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+	eexpr "github.com/viant/velty/est/expr"
+	"github.com/viant/velty/est/op"
+	"reflect"
+)
+
+var (
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	stringType    = reflect.TypeOf("")
+)
+
+func (p *Planner) compileList(actual *expr.List) (*op.Expression, error) {
+	elements, elemType, err := p.compileElements(actual.Elements)
+	if err != nil {
+		return nil, err
+	}
+
+	sliceType := reflect.SliceOf(elemType)
+	acc := p.accumulator(sliceType)
+	return &op.Expression{
+		Type: sliceType,
+		New:  eexpr.List(elements, &op.Expression{Selector: acc, Type: sliceType}),
+	}, nil
+}
+
+func (p *Planner) compileMap(actual *expr.Map) (*op.Expression, error) {
+	keys, keyType, err := p.compileElements(actual.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	values, valueType, err := p.compileElements(actual.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType == interfaceType {
+		keyType = stringType
+	}
+
+	if !keyType.Comparable() {
+		return nil, fmt.Errorf("unsupported map literal key type: %v", keyType.String())
+	}
+
+	mapType := reflect.MapOf(keyType, valueType)
+	acc := p.accumulator(mapType)
+	return &op.Expression{
+		Type: mapType,
+		New:  eexpr.Map(keys, values, &op.Expression{Selector: acc, Type: mapType}),
+	}, nil
+}
+
+//compileElements compiles collection elements and infers their common type, falling back to interface{}
+func (p *Planner) compileElements(elements []ast.Expression) ([]*op.Expression, reflect.Type, error) {
+	result := make([]*op.Expression, len(elements))
+	var commonType reflect.Type
+	for i, element := range elements {
+		compiled, err := p.compileExpr(element)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result[i] = compiled
+		switch {
+		case compiled.Type == nil:
+			commonType = interfaceType
+		case i == 0:
+			commonType = compiled.Type
+		case commonType != compiled.Type:
+			commonType = interfaceType
+		}
+	}
+
+	if commonType == nil {
+		commonType = interfaceType
+	}
+	return result, commonType, nil
+}
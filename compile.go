@@ -1,6 +1,7 @@
 package velty
 
 import (
+	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/stmt"
 	"github.com/viant/velty/est"
 	"github.com/viant/velty/parser"
@@ -10,11 +11,17 @@ import (
 
 //Compile create Execution Plan and State provider for the Execution Plan.
 func (p *Planner) Compile(template []byte) (*est.Execution, func() *est.State, error) {
-	root, err := parser.Parse(template)
+	p.source = &ast.Source{Name: p.templateName, Input: template}
+	root, err := parser.ParseTemplate(p.templateName, template, p.spaceGobbling)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	p.sections, p.inputs = nil, nil
+	if root, err = p.extend(root); err != nil {
+		return nil, nil, err
+	}
+
 	exec, err := p.newExecution(root)
 	if err != nil {
 		return nil, nil, err
@@ -35,6 +42,7 @@ func (p *Planner) stateProvider() func() *est.State {
 			PanicOnError: p.panicOnError,
 		}
 
+		state.Buffer.SetTimeLayout(p.timeLayout)
 		return state
 	}
 }
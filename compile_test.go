@@ -2,6 +2,8 @@ package velty_test
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/velty"
@@ -9,13 +11,31 @@ import (
 	"github.com/viant/velty/est"
 	"github.com/viant/velty/est/op"
 	"github.com/viant/velty/functions"
+	"math"
 	"net/http"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
+var templates = fstest.MapFS{
+	"header.vm":  {Data: []byte(`[$name]#set($title = "Title")#macro(greet $n)Hello $n#end`)},
+	"static.txt": {Data: []byte(`static $name`)},
+	"cycle.vm":   {Data: []byte(`#parse("cycle2.vm")`)},
+	"cycle2.vm":  {Data: []byte(`#parse("cycle.vm")`)},
+	"self.vm":    {Data: []byte(`self#parse($self)`)},
+	"a.vm":       {Data: []byte(`a#parse($b)`)},
+	"b.vm":       {Data: []byte(`b#parse($a)`)},
+	"broken.vm":  {Data: []byte("ok\n$foo.Missing")},
+	"layout.vm":  {Data: []byte(`<title>#block("title")Default#end</title><body>#block("content")#end</body>`)},
+	"section.vm": {Data: []byte(`#extends("layout.vm")#block("content")<main>#block("main")none#end</main>#end`)},
+	"count.vm":   {Data: []byte(`[$foreach.count]`)},
+	"stop.vm":    {Data: []byte(`S#stop`)},
+	"extends.vm": {Data: []byte(`#extends("extends.vm")`)},
+}
+
 type Node struct {
 	DB      *sql.DB
 	Request *http.Request
@@ -33,6 +53,68 @@ func (b *bar) Concat(values ...string) string {
 	return strings.Join(append([]string{b.Name}, values...), " ")
 }
 
+type customer struct {
+	FirstName string
+	LastName  string
+	Orders    int
+}
+
+func (c *customer) GetFullName() string {
+	return c.FirstName + " " + c.LastName
+}
+
+func (c *customer) IsActive() bool {
+	return c.Orders > 0
+}
+
+func (c *customer) Initials() string {
+	return c.FirstName[:1] + c.LastName[:1]
+}
+
+type reading struct {
+	ID    int64
+	Count uint32
+	Level int8
+	Price float32
+	Limit *int
+	Ratio *float64
+	Total uint64
+	Quota *uint
+}
+
+type event struct {
+	At       time.Time
+	End      *time.Time
+	Duration time.Duration
+}
+
+func newEvent() *event {
+	at := time.Date(2024, 3, 15, 10, 30, 45, 0, time.UTC)
+	end := at.Add(2 * time.Hour)
+	return &event{At: at, End: &end, Duration: time.Hour}
+}
+
+type (
+	address struct {
+		City string
+	}
+
+	buyer struct {
+		Name    string
+		Age     int
+		Address *address
+	}
+
+	order struct {
+		ID       int
+		Customer *buyer
+	}
+
+	settings struct {
+		Config interface{}
+	}
+)
+
 type (
 	barAggregator struct{}
 	barAggregates struct {
@@ -136,6 +218,13 @@ func TestPlanner_Compile(t *testing.T) {
 		Foo
 	}
 
+	type Inventory struct {
+		Name   string
+		Boo    *Boo
+		Counts map[string]int
+		Items  []string
+	}
+
 	values := &Values{
 		StringValue:  "employee street",
 		IntValue:     123456789,
@@ -266,6 +355,42 @@ func TestPlanner_Compile(t *testing.T) {
 			template:    `#set( $var1 = "abc" + "cdef")$var1`,
 			expect:      "abccdef",
 		},
+		{
+			description: `assign nested struct fields`,
+			template:    `#set($inv.Name = "main")#set($inv.Boo.Price = 1.5)$inv.Name $inv.Boo.Price`,
+			definedVars: map[string]interface{}{"inv": &Inventory{Boo: &Boo{}}},
+			expect:      "main 1.5",
+		},
+		{
+			description: `assign nested map entries`,
+			template:    `#set($inv.Counts["a"] = 2)#set($inv.Counts["b"] = $inv.Counts["a"] + 1)$inv.Counts["a"],$inv.Counts["b"]`,
+			definedVars: map[string]interface{}{"inv": &Inventory{}},
+			expect:      "2,3",
+		},
+		{
+			description: `assign map entry`,
+			template:    `#set($m["k"] = "v")#set($m[$key] = 1)$m["k"] $m["n"]`,
+			definedVars: map[string]interface{}{"m": map[string]interface{}{}, "key": "n"},
+			expect:      "v 1",
+		},
+		{
+			description: `assign nested slice element`,
+			template:    `#set($inv.Items[1] = "y")$inv.Items`,
+			definedVars: map[string]interface{}{"inv": &Inventory{Items: []string{"a", "b"}}},
+			expect:      `["a","y"]`,
+		},
+		{
+			description: `assign map value field`,
+			template:    `#set($m["k"].Price = 1.0)`,
+			definedVars: map[string]interface{}{"m": map[string]Boo{}},
+			expectError: true,
+		},
+		{
+			description: `assign function result`,
+			template:    `#set($bar.UpperCase() = "x")`,
+			definedVars: map[string]interface{}{"bar": &bar{}},
+			expectError: true,
+		},
 		{
 			description: `assign binary expression, comparison #6`,
 			template:    `#set( $var1 = "abc" == "cdef")$var1`,
@@ -627,6 +752,433 @@ $abc
 `,
 			expect: "\n \n\t10 \n \n\t9 \n \n\t8 \n \n\t7 \n \n\t6 \n \n\t5 \n \n\t4 \n \n\t3 \n \n\t2 \n \n\t1 \n \n\t0 \n \n\t-1 \n \n\t-2 \n \n\t-3 \n \n\t-4 \n \n\t-5 \n \n\t-6 \n \n\t-7 \n \n\t-8 \n \n\t-9 \n\n",
 		},
+		{
+			description: "list literal",
+			template:    `#set($list = [1, 2, $num])$list #foreach($item in $list)$item;#end`,
+			definedVars: map[string]interface{}{"num": 3},
+			expect:      "[1,2,3] 1;2;3;",
+		},
+		{
+			description: "list literal in foreach",
+			template:    `#foreach($item in ["a", "b", $name])$item;#end`,
+			definedVars: map[string]interface{}{"name": "c"},
+			expect:      "a;b;c;",
+		},
+		{
+			description: "mixed list literal",
+			template:    `#set($list = [1, "a", true])$list #foreach($item in $list)$item;#end`,
+			expect:      `[1,"a",true] 1;a;true;`,
+		},
+		{
+			description: "empty list and map literals",
+			template:    `#set($list = [])#set($aMap = {})$list $aMap`,
+			expect:      "[] {}",
+		},
+		{
+			description: "map literal",
+			template:    `#set($aMap = {"a": 1, "b": $num})$aMap["b"] $aMap`,
+			definedVars: map[string]interface{}{"num": 2},
+			expect:      `2 {"a":1,"b":2}`,
+		},
+		{
+			description: "nested map literal",
+			template:    `#set($aMap = {"list": [1, 2], "map": {"ok": true}, "name": "abc"})$aMap`,
+			expect:      `{"list":[1,2],"map":{"ok":true},"name":"abc"}`,
+		},
+		{
+			description: "list literal as function argument",
+			template:    `$slices.Length([1, 2, 3]) $slices.StringAt(["a", "b"], 1)`,
+			expect:      "3 b",
+		},
+		{
+			description: "inclusive range",
+			template:    `#foreach($i in [1..5])$i#end`,
+			expect:      "12345",
+		},
+		{
+			description: "descending inclusive range",
+			template:    `#foreach($i in [3..-1])$i;#end`,
+			expect:      "3;2;1;0;-1;",
+		},
+		{
+			description: "single element range",
+			template:    `#foreach($i in [2..2])$i#end`,
+			expect:      "2",
+		},
+		{
+			description: "range with expression bounds",
+			template:    `#foreach($i in [$from..$count])$i;#end #foreach($i in [$count..($from + 1)])$i;#end`,
+			definedVars: map[string]interface{}{"from": 1, "count": 4},
+			expect:      "1;2;3;4; 4;3;2;",
+		},
+		{
+			description: "range assignment",
+			template:    `#set($list = [1..$count])$list`,
+			definedVars: map[string]interface{}{"count": 3},
+			expect:      "[1,2,3]",
+		},
+		{
+			description: "range with non int bound",
+			template:    `#foreach($i in [1..$name])$i#end`,
+			definedVars: map[string]interface{}{"name": "abc"},
+			expectError: true,
+		},
+		{
+			description: "operators precedence",
+			template:    `#set($x = $num * 2 + 1)$x #set($y = 10 - 2 - 3)$y #set($z = 2 + 3 * 4 - 6 / 2)$z`,
+			definedVars: map[string]interface{}{"num": 3},
+			expect:      "7 5 11",
+		},
+		{
+			description: "modulo",
+			template:    `#set($i = 7 % 3)$i #set($f = 7.5 % 2)$f #foreach($item in [1..6])#if($item % 2 == 0)$item#end#end`,
+			expect:      "1 1.5 246",
+		},
+		{
+			description: "modulo and division by zero",
+			template:    `#set($i = $num % 0)$i #set($j = $num / 0)$j`,
+			definedVars: map[string]interface{}{
+				"num": 7,
+			},
+			expectTemplateErr: true,
+			expect:            "0 0",
+		},
+		{
+			description: "logical operators precedence",
+			template:    `#if($num == 3 && $name == "abc")yes#end #if($num == 1 || $num == 3 && $name != "abc")no#{else}yes#end`,
+			definedVars: map[string]interface{}{"num": 3, "name": "abc"},
+			expect:      "yes yes",
+		},
+		{
+			description: "word operators",
+			template:    `#if($num eq 3 and $name ne "xyz")a#end#if($num gt 2 and $num ge 3 and $num lt 4 and $num le 3)b#end#if(not $flag or $num eq 0)c#end`,
+			definedVars: map[string]interface{}{"num": 3, "name": "abc", "flag": false},
+			expect:      "abc",
+		},
+		{
+			description: "string comparison",
+			template:    `#if($name < "abd" && $name >= "abc")yes#end`,
+			definedVars: map[string]interface{}{"name": "abc"},
+			expect:      "yes",
+		},
+		{
+			description: "short circuit",
+			template:    `#if($num == 1 && $slices.IntAt($values, 5) == 1)a#end#if($num == 3 || $slices.IntAt($values, 5) == 1)b#end`,
+			definedVars: map[string]interface{}{"num": 3, "values": []int{1}},
+			expect:      "b",
+		},
+		{
+			description: "ternary",
+			template:    `#set($sign = $num > 0 ? "positive" : "negative")$sign #set($size = $num > 5 ? "big" : $num > 1 ? "medium" : "small")$size #set($value = $flag ? 1 : 2.5)$value`,
+			definedVars: map[string]interface{}{"num": 3, "flag": false},
+			expect:      "positive medium 2.5",
+		},
+		{
+			description: "ternary with non bool condition",
+			template:    `#set($value = $num ? 1 : 2)`,
+			definedVars: map[string]interface{}{"num": 3},
+			options:     []velty.Option{velty.StrictTruthiness},
+			expectError: true,
+		},
+		{
+			description: "ternary with truthy condition",
+			template:    `#set($value = $num ? 1 : 2)$value`,
+			definedVars: map[string]interface{}{"num": 3},
+			expect:      "1",
+		},
+		{
+			description: "foreach break",
+			template:    `#foreach($v in $values)#if($v == 3)#break#end$v #end|done`,
+			definedVars: map[string]interface{}{"values": []int{1, 2, 3, 4}},
+			expect:      "1 2 |done",
+		},
+		{
+			description: "foreach continue",
+			template:    `#foreach($v in $values)#if($v % 2 == 0)#continue#end$v #end|done`,
+			definedVars: map[string]interface{}{"values": []int{1, 2, 3, 4, 5}},
+			expect:      "1 3 5 |done",
+		},
+		{
+			description: "foreach continue over structs",
+			template:    `#foreach($v in $values)#if($v.Name == "b")#continue#end$v.Name #end|done`,
+			definedVars: map[string]interface{}{"values": []*bar{{Name: "a"}, {Name: "b"}, {Name: "c"}}},
+			expect:      "a c |done",
+		},
+		{
+			description: "for continue",
+			template:    `#for($i = 0; $i < 5; $i++)#if($i == 2)#continue#end$i#end`,
+			expect:      "0134",
+		},
+		{
+			description: "range break and continue",
+			template:    `#foreach($i in [1..10])#if($i == 2)#continue#end#if($i > 4)#break#end$i#end`,
+			expect:      "134",
+		},
+		{
+			description: "nested loop break",
+			template:    `#foreach($i in [1..3])#foreach($j in [1..3])#if($j > $i)#break#end$i$j #end|#end`,
+			expect:      "11 |21 22 |31 32 33 |",
+		},
+		{
+			description: "stop",
+			template:    `a#foreach($i in [1..3])$i#if($i == 2)#stop#end#end b`,
+			expect:      "a12",
+		},
+		{
+			description: "break in macro",
+			template:    `#macro(greet $name)Hello#if($name == "")#break#end $name#end#greet("")! #greet("Bob")!`,
+			expect:      "Hello! Hello Bob!",
+		},
+		{
+			description: "break outside loop",
+			template:    `a#break b`,
+			expect:      "a",
+		},
+		{
+			description: "break scoped to loop",
+			template:    `#foreach($i in [1..3])#if($i == 2)#break#end$i#end after #foreach($j in [1..2])$j#end`,
+			expect:      "1 after 12",
+		},
+		{
+			description: "continue in macro",
+			template:    `#macro(skip $v)#if($v == 2)#continue#end#end#foreach($v in [1..3])#skip($v)$v#end`,
+			expect:      "13",
+		},
+		{
+			description: "stop in macro",
+			template:    `#macro(halt)#stop#end#foreach($v in [1..3])$v#if($v == 2)#halt()#end#end after`,
+			expect:      "12",
+		},
+		{
+			description: "stop in reused macro instance",
+			template:    `#macro(halt $v)#if($v == 2)#stop#end#end#halt(0)#for($i = 0; $i < 4; $i++)$i#halt($i)#end after`,
+			expect:      "012",
+		},
+		{
+			description: "stop scoped to its loop",
+			template:    `#foreach($i in [1..3])$i#end #foreach($j in [1..3])$j#if($j == 2)#stop#end#end after`,
+			expect:      "123 12",
+		},
+		{
+			description: "stop in evaluate",
+			template:    `a#evaluate("S#stop")b`,
+			expect:      "aS",
+		},
+		{
+			description: "stop in dynamic parse",
+			template:    `a#parse($name)b`,
+			definedVars: map[string]interface{}{"name": "stop.vm"},
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "aS",
+		},
+		{
+			description: "stop in parse",
+			template:    `a#parse("stop.vm")b`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "aS",
+		},
+		{
+			description: "break prefixed text",
+			template:    `Menu: #breakfast and lunch`,
+			expect:      "Menu: #breakfast and lunch",
+		},
+		{
+			description: "stop and continue prefixed text",
+			template:    `Bus #stopped here, see #continued`,
+			expect:      "Bus #stopped here, see #continued",
+		},
+		{
+			description: "break prefixed macro call",
+			template:    `#macro(breakfast)eggs#end#breakfast()`,
+			expect:      "eggs",
+		},
+		{
+			description: "foreach hasNext",
+			template:    `#foreach($v in $values)$v#if($foreach.hasNext), #end#end`,
+			definedVars: map[string]interface{}{"values": []string{"a", "b", "c"}},
+			expect:      "a, b, c",
+		},
+		{
+			description: "foreach metadata",
+			template:    `#foreach($v in $values)[$foreach.index $foreach.count $foreach.first $foreach.last $velocityCount]#end`,
+			definedVars: map[string]interface{}{"values": []*bar{{Name: "a"}, {Name: "b"}}},
+			expect:      "[0 1 true false 1][1 2 false true 2]",
+		},
+		{
+			description: "nested foreach metadata",
+			template:    `#foreach($i in $values)#foreach($j in [1..2])$foreach.parent.count$i$foreach.count #end$foreach.count|#end`,
+			definedVars: map[string]interface{}{"values": []string{"a", "b"}},
+			expect:      "1a1 1a2 1|2b1 2b2 2|",
+		},
+		{
+			description: "range metadata",
+			template:    `#foreach($i in [3..1])$i#if(!$foreach.last),#end#end`,
+			expect:      "3,2,1",
+		},
+		{
+			description: "foreach metadata in evaluate",
+			template:    `#foreach($v in $values)#evaluate($template)#end`,
+			definedVars: map[string]interface{}{"values": []string{"a", "b"}, "template": "$foreach.count"},
+			expect:      "12",
+		},
+		{
+			description: "foreach metadata in dynamic parse",
+			template:    `#foreach($v in $values)#parse($name)#end`,
+			definedVars: map[string]interface{}{"values": []string{"a", "b"}, "name": "count.vm"},
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "[1][2]",
+		},
+		{
+			description: "foreach metadata in macro called from different loops",
+			template:    `#macro(m)$foreach.count#end#foreach($i in $a)#m()#end|#foreach($j in $b)#m()#end`,
+			definedVars: map[string]interface{}{"a": []int{1, 2}, "b": []int{1, 2, 3}},
+			expect:      "12|123",
+		},
+		{
+			description: "foreach else",
+			template:    `#foreach($v in $values)$v #else empty#end|#foreach($v in $other)$v#else empty#end`,
+			definedVars: map[string]interface{}{"values": []int{}, "other": []int{1, 2}},
+			expect:      " empty|12",
+		},
+		{
+			description: "foreach else nil slice",
+			template:    `#foreach($v in $values)$v.Name#else none#end`,
+			definedVars: map[string]interface{}{"values": []*bar(nil)},
+			expect:      " none",
+		},
+		{
+			description: "foreach else nil parent",
+			template:    `#foreach($v in $agg.Names)$v#else none#end`,
+			definedVars: map[string]interface{}{"agg": (*barAggregates)(nil)},
+			expect:      " none",
+		},
+		{
+			description: "foreach else unresolved",
+			template:    `#foreach($v in $missing)$v#else none#end`,
+			expect:      " none",
+		},
+		{
+			description: "foreach else with nested if",
+			template:    `#foreach($v in $values)#if($v > 1)$v#else-#end#else none#end`,
+			definedVars: map[string]interface{}{"values": []int{1, 2}},
+			expect:      "-2",
+		},
+		{
+			description: "range else",
+			template:    `#foreach($i in [0...$count])$i#else none#end`,
+			definedVars: map[string]interface{}{"count": 0},
+			expect:      " none",
+		},
+		{
+			description: "foreach map sorted",
+			template:    `#foreach($e in $m)$e.Key=$e.Value#if($foreach.hasNext);#end#end`,
+			definedVars: map[string]interface{}{"m": map[string]int{"b": 2, "a": 1, "c": 3}},
+			options:     []velty.Option{velty.SortMapKeys(true)},
+			expect:      "a=1;b=2;c=3",
+		},
+		{
+			description: "foreach map",
+			template:    `#foreach($e in $m)$e.Key:$e.Value.Name $foreach.last#end`,
+			definedVars: map[string]interface{}{"m": map[int]*bar{1: {Name: "x"}}},
+			expect:      "1:x true",
+		},
+		{
+			description: "foreach map break",
+			template:    `#foreach($e in $m)$e.Key#if($e.Key == 2)#break#end#end`,
+			definedVars: map[string]interface{}{"m": map[int]bool{1: true, 2: true, 3: true}},
+			options:     []velty.Option{velty.SortMapKeys(true)},
+			expect:      "12",
+		},
+		{
+			description: "foreach empty map",
+			template:    `#foreach($e in $m)$e.Key#else empty#end`,
+			definedVars: map[string]interface{}{"m": map[string]int(nil)},
+			expect:      " empty",
+		},
+		{
+			description: "foreach sorted map with entry deleted by the body",
+			template:    `#foreach($e in $m)$e.Key$m.drop("b")#end`,
+			definedVars: map[string]interface{}{"m": map[string]int{"a": 1, "b": 2, "c": 3}},
+			functions: map[string]interface{}{
+				"drop": func(m map[string]int, key string) string {
+					delete(m, key)
+					return ""
+				},
+			},
+			options: []velty.Option{velty.SortMapKeys(true)},
+			expect:  "ac",
+		},
+		{
+			description: "foreach channel",
+			template:    `#foreach($v in $ch)$velocityCount:$v #end`,
+			definedVars: map[string]interface{}{"ch": intChan(5, 6, 7)},
+			expect:      "1:5 2:6 3:7 ",
+		},
+		{
+			description: "foreach unsupported set",
+			template:    `#foreach($v in $value)$v#end`,
+			definedVars: map[string]interface{}{"value": 10},
+			expectError: true,
+		},
+		{
+			description: "block comment",
+			template:    "a#* skipped $x #if *#b#*\n multi\n line\n*#c",
+			expect:      "abc",
+		},
+		{
+			description: "line comment keeps preceding text",
+			template:    "abc ## comment\ndef",
+			expect:      "abc def",
+		},
+		{
+			description: "unterminated block comment",
+			template:    "abc #* comment",
+			expectError: true,
+		},
+		{
+			description: "space gobbling none",
+			template:    "a\n  #if(true)\n  x\n  #end\nb",
+			expect:      "a\n  \n  x\n  \nb",
+		},
+		{
+			description: "space gobbling bc",
+			template:    "a\n  #set($v = 1)\n  #if(true)\n  x$v\n  #end\nb",
+			options:     []velty.Option{velty.GobbleBC},
+			expect:      "a\n    x1\n  b",
+		},
+		{
+			description: "space gobbling lines",
+			template:    "a\n  #if(true)\n  x\n  #end ## comment\n  #set($v = 1)  \nb$v #if(true)y#end\n",
+			options:     []velty.Option{velty.GobbleLines},
+			expect:      "a\n  x\nb1 y\n",
+		},
+		{
+			description: "space gobbling structured",
+			template:    "<table>\n  #foreach($row in $rows)\n    <tr>\n      #foreach($cell in $row)\n        <td>$cell</td>\n      #end\n    </tr>\n  #end\n</table>",
+			definedVars: map[string]interface{}{"rows": [][]int{{1, 2}, {3}}},
+			options:     []velty.Option{velty.GobbleStructured},
+			expect:      "<table>\n  <tr>\n    <td>1</td>\n    <td>2</td>\n  </tr>\n  <tr>\n    <td>3</td>\n  </tr>\n</table>",
+		},
+		{
+			description: "javabean properties",
+			template:    `$customer.firstName $customer.fullName $customer.active $customer.initials $customer.isActive() $customer.getFullName()`,
+			definedVars: map[string]interface{}{"customer": &customer{FirstName: "John", LastName: "Doe", Orders: 2}},
+			options:     []velty.Option{velty.JavaBeanProperties},
+			expect:      "John John Doe true JD true John Doe",
+		},
+		{
+			description: "javabean properties disabled",
+			template:    `$customer.FirstName $customer.firstName`,
+			definedVars: map[string]interface{}{"customer": &customer{FirstName: "John"}},
+			expect:      "John $customer.firstName",
+		},
+		{
+			description: "javabean unknown property",
+			template:    `$customer.middleName`,
+			definedVars: map[string]interface{}{"customer": &customer{FirstName: "John"}},
+			options:     []velty.Option{velty.JavaBeanProperties},
+			expect:      "$customer.middleName",
+		},
 		{
 			description: "method receiver",
 			template:    `$bar.UpperCase()`,
@@ -779,6 +1331,41 @@ $abc
 			template:    `$strings.ToUpper("abc")`,
 			expect:      `ABC`,
 		},
+		{
+			description: `string interpolation`,
+			template:    `#set($msg = "Hello $name, you have ${count} items.")$msg`,
+			definedVars: map[string]interface{}{"name": "Bob", "count": 3},
+			expect:      `Hello Bob, you have 3 items.`,
+		},
+		{
+			description: `string interpolation of numbers`,
+			template:    `#set($v = "$x$y")$v`,
+			definedVars: map[string]interface{}{"x": 1, "y": 2},
+			expect:      `12`,
+		},
+		{
+			description: `string interpolation unresolved reference`,
+			template:    `#set($v = "a $missing $!quiet b")$v`,
+			expect:      `a $missing  b`,
+		},
+		{
+			description: `string interpolation in function argument`,
+			template:    `$strings.ToUpper("hi $name")`,
+			definedVars: map[string]interface{}{"name": "Bob"},
+			expect:      `HI BOB`,
+		},
+		{
+			description: `single quoted raw string`,
+			template:    `#set($v = 'raw $name, \'quoted\'')$v $strings.ToUpper('a, b')#if($name == 'Bob') eq#end`,
+			definedVars: map[string]interface{}{"name": "Bob"},
+			expect:      `raw $name, 'quoted' A, B eq`,
+		},
+		{
+			description: `reference followed by dot`,
+			template:    `Hello $name.`,
+			definedVars: map[string]interface{}{"name": "Bob"},
+			expect:      `Hello Bob.`,
+		},
 		{
 			description: `built in slices.Length function`,
 			template:    `$slices.Length($foos)`,
@@ -933,59 +1520,262 @@ $lastColumnName`,
 			expect:      `"2014-11-12T11:45:26Z"`,
 		},
 		{
-			description: `slices`,
-			template:    `$values[2]`,
-			expect:      `second value`,
+			description: `time layout`,
+			template:    `$time.Now() $event.At $event.End|`,
+			definedVars: map[string]interface{}{"event": &event{At: time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)}},
+			options:     []velty.Option{velty.TimeLayout("2006-01-02 15:04")},
+			expect:      `2014-11-12 11:45 2024-03-15 10:30 |`,
+		},
+		{
+			description: `time methods`,
+			template:    `$event.At.Format("Jan 2, 2006") $event.At.AddDate(0, 1, 0).Format("2006-01-02") $event.At.Add($event.Duration).Truncate($event.Duration).Hour() $event.At.Unix() $event.At.Before($event.End)`,
+			definedVars: map[string]interface{}{"event": newEvent()},
+			expect:      `Mar 15, 2024 2024-04-15 11 1710498645 true`,
+		},
+		{
+			description: `time comparison`,
+			template:    `#if($event.At < $event.End)a#end#if($event.End >= $time.Now())b#end#if($event.At == $event.At.UTC())c#end#if($event.At != $event.End)d#end#if($event.At > $event.End)e#end`,
+			definedVars: map[string]interface{}{"event": newEvent()},
+			expect:      `abcd`,
+		},
+		{
+			description: `time java pattern format`,
+			template:    `$date.format("yyyy-MM-dd'T'HH:mm:ss", $event.At) $date.format('EEE, d MMM yy hh:mm a', $event.End)`,
+			definedVars: map[string]interface{}{"event": newEvent()},
+			expect:      `2024-03-15T10:30:45 Fri, 15 Mar 24 12:30 PM`,
+		},
+		{
+			description: `time parse`,
+			template:    `#set($at = $date.parse("dd/MM/yyyy", "06/05/2024"))$at #set($local = $time.ParseInLocation("2006-01-02 15:04", "2024-05-06 10:00", "America/New_York"))$local.Unix() $time.Unix(0).UTC()`,
+			expect:      `"2024-05-06T00:00:00Z" 1715004000 "1970-01-01T00:00:00Z"`,
+		},
+		{
+			description:       `time parse error`,
+			template:          `#set($at = $time.Parse("2006-01-02", "invalid"))$at`,
+			expect:            `"0001-01-01T00:00:00Z"`,
+			expectTemplateErr: true,
+		},
+		{
+			description: `variable shadows function namespace`,
+			template:    `#set($date = "today")$date`,
+			expect:      `today`,
+		},
+		{
+			description: `alternate value`,
+			template:    `${name|'anonymous'} ${title|"Dear $name"} ${missing|'none'} ${count|-1}`,
 			definedVars: map[string]interface{}{
-				"values": []string{"zero value", "first value", "second value", "third value"},
+				"name":  "",
+				"title": "",
+				"count": 0,
 			},
+			expect: `anonymous Dear  none -1`,
 		},
 		{
-			description: `types difference`,
-			template:    `#set($value = 2.5 + $anInt)$value`,
-			expect:      `3.5`,
+			description: `alternate value not used`,
+			template:    `${name|'anonymous'} ${count|-1}`,
 			definedVars: map[string]interface{}{
-				"anInt": 1,
+				"name":  "Bob",
+				"count": 3,
 			},
+			expect: `Bob 3`,
 		},
 		{
-			description: `types difference #2`,
-			template:    `#set($value = ("Values: " + 1) + (" another one: " + 5.21))$value`,
-			expect:      `Values: 1 another one: 5.21`,
+			description: `alternate value in strict mode`,
+			template:    `${missing|'none'}`,
+			options:     []velty.Option{velty.StrictReferences},
+			expect:      `none`,
 		},
 		{
-			description: `map get | exported`,
-			template:    `$Data["key"]`,
-			expect:      `value`,
+			description: `null-safe navigation`,
+			template:    `${order?.Customer?.Address?.City|'n/a'} ${order?.Customer.Age|'unknown'} #if($order?.Customer?.Name)named#else anonymous#end`,
 			definedVars: map[string]interface{}{
-				"Data": map[string]interface{}{
-					"key": "value",
-				},
+				"order": &order{ID: 1},
 			},
+			expect: `n/a unknown  anonymous`,
 		},
 		{
-			description: `map get | unexported`,
-			template:    `$data["key"]`,
-			expect:      `value`,
+			description: `null-safe navigation resolved`,
+			template:    `${order?.Customer?.Address?.City|'n/a'} ${order?.Customer.Age|'unknown'} $order?.Customer?.Name`,
 			definedVars: map[string]interface{}{
-				"data": map[string]interface{}{
-					"key": "value",
-				},
+				"order": &order{ID: 1, Customer: &buyer{Name: "Bob", Age: 30, Address: &address{City: "Paris"}}},
 			},
+			expect: `Paris 30 Bob`,
 		},
 		{
-			description: `map get | has true`,
-			template:    `$data.HasKey("key")`,
-			expect:      `true`,
+			description: `null-safe navigation assignment`,
+			template:    `#set($city = ${order?.Customer?.Address?.City|$fallback})$city`,
 			definedVars: map[string]interface{}{
-				"data": map[string]interface{}{
-					"key": "value",
-				},
+				"order":    &order{ID: 1, Customer: &buyer{Name: "Bob"}},
+				"fallback": "unknown",
 			},
+			expect: `unknown`,
 		},
 		{
-			description: `map get | has false`,
-			template:    `$data.HasKey("abc")`,
+			description: `map dot access`,
+			template:    `$m.a $m.b.Name #set($m.c = $m.a)$m.c`,
+			definedVars: map[string]interface{}{
+				"m": map[string]string{"a": "1"},
+			},
+			expect: `1 $m.b.Name 1`,
+		},
+		{
+			description: `map of structs dot access`,
+			template:    `$bars.first.Name $bars.second.Name`,
+			definedVars: map[string]interface{}{
+				"bars": map[string]*bar{"first": {Name: "abc"}, "second": {Name: "def"}},
+			},
+			expect: `abc def`,
+		},
+		{
+			description: `decoded JSON dot access`,
+			template:    `$cfg.db.host:$cfg.db.port $cfg.db.tags[1] ${cfg.db.user|'root'} $settings.Config.db.host`,
+			definedVars: map[string]interface{}{
+				"cfg":      decodeJSON(`{"db":{"host":"localhost","port":5432,"tags":["primary","replica"]}}`),
+				"settings": &settings{Config: decodeJSON(`{"db":{"host":"remote"}}`)},
+			},
+			expect: `localhost:5432 replica root remote`,
+		},
+		{
+			description: `interface dot access dispatched by value type`,
+			template:    `#foreach($item in $items)$item.Config.name,#end`,
+			definedVars: map[string]interface{}{
+				"items": []*settings{
+					{Config: map[string]interface{}{"name": "json"}},
+					{Config: &bar{Name: "struct"}},
+					{Config: map[string]string{"name": "map"}},
+					{Config: map[string]interface{}{"name": "json again"}},
+				},
+			},
+			expect: `json,struct,map,json again,`,
+		},
+		{
+			description: `global scoping set in loop`,
+			template:    `#set($x = "outer")#foreach($i in [1..2])#set($x = $i)$x,#end$x`,
+			expect:      `1,2,2`,
+		},
+		{
+			description: `block scoping set in loop`,
+			template:    `#set($x = "outer")#foreach($i in [1..2])#set($x = $i)$x,#end$x`,
+			options:     []velty.Option{velty.BlockScoping},
+			expect:      `1,2,outer`,
+		},
+		{
+			description: `block scoping loop item`,
+			template:    `#set($name = "keep")#foreach($name in $names)$name,#end$name`,
+			options:     []velty.Option{velty.BlockScoping},
+			definedVars: map[string]interface{}{
+				"names": []string{"a", "b"},
+			},
+			expect: `a,b,keep`,
+		},
+		{
+			description: `block scoping set in macro`,
+			template:    `#set($x = 1)#macro(m $a)#set($x = $a)$x#end#m(5) $x`,
+			options:     []velty.Option{velty.BlockScoping},
+			expect:      `5 1`,
+		},
+		{
+			description: `block scoping global escape`,
+			template:    `#set($total = 0)#foreach($i in [1..3])#set($global.total = $total + $i)#set($global.last = $i)#end$total $last`,
+			options:     []velty.Option{velty.BlockScoping},
+			expect:      `6 3`,
+		},
+		{
+			description: `block scoping global escape of shadowed variable`,
+			template:    `#set($x = "o")#foreach($i in [1..1])#set($x = "local")#foreach($j in [1..1])#set($global.x = "global")$x $global.x#end#end $x`,
+			options:     []velty.Option{velty.BlockScoping},
+			expect:      `local global global`,
+		},
+		{
+			description: `template declared inputs`,
+			template:    `#param($limit int)#var($names []string)#foreach($name in $names)#if($foreach.count <= $limit)$name #end#end`,
+			definedVars: map[string]interface{}{
+				"limit": 2,
+				"names": []string{"a", "b", "c"},
+			},
+			expect: `a b `,
+		},
+		{
+			description: `template declared input with TypeParser`,
+			template:    `#param($buyer "model.Buyer")$buyer.Name $buyer.Address.City`,
+			options: []velty.Option{
+				velty.TypeParser(func(typeRepresentation string) (reflect.Type, error) {
+					if typeRepresentation != "model.Buyer" {
+						return nil, fmt.Errorf("unknown type: %v", typeRepresentation)
+					}
+					return reflect.TypeOf(&buyer{}), nil
+				}),
+			},
+			definedVars: map[string]interface{}{
+				"buyer": &buyer{Name: "Ann", Address: &address{City: "Austin"}},
+			},
+			expect: `Ann Austin`,
+		},
+		{
+			description: `template declared input of unknown type`,
+			template:    `#param($buyer model.Buyer)$buyer.Name`,
+			expectError: true,
+		},
+		{
+			description: `template declared input conflicting with variable`,
+			template:    `#param($limit string)$limit`,
+			definedVars: map[string]interface{}{"limit": 2},
+			expectError: true,
+		},
+		{
+			description: `slices`,
+			template:    `$values[2]`,
+			expect:      `second value`,
+			definedVars: map[string]interface{}{
+				"values": []string{"zero value", "first value", "second value", "third value"},
+			},
+		},
+		{
+			description: `types difference`,
+			template:    `#set($value = 2.5 + $anInt)$value`,
+			expect:      `3.5`,
+			definedVars: map[string]interface{}{
+				"anInt": 1,
+			},
+		},
+		{
+			description: `types difference #2`,
+			template:    `#set($value = ("Values: " + 1) + (" another one: " + 5.21))$value`,
+			expect:      `Values: 1 another one: 5.21`,
+		},
+		{
+			description: `map get | exported`,
+			template:    `$Data["key"]`,
+			expect:      `value`,
+			definedVars: map[string]interface{}{
+				"Data": map[string]interface{}{
+					"key": "value",
+				},
+			},
+		},
+		{
+			description: `map get | unexported`,
+			template:    `$data["key"]`,
+			expect:      `value`,
+			definedVars: map[string]interface{}{
+				"data": map[string]interface{}{
+					"key": "value",
+				},
+			},
+		},
+		{
+			description: `map get | has true`,
+			template:    `$data.HasKey("key")`,
+			expect:      `true`,
+			definedVars: map[string]interface{}{
+				"data": map[string]interface{}{
+					"key": "value",
+				},
+			},
+		},
+		{
+			description: `map get | has false`,
+			template:    `$data.HasKey("abc")`,
 			expect:      `false`,
 			definedVars: map[string]interface{}{
 				"data": map[string]interface{}{
@@ -1211,6 +2001,107 @@ $lastColumnName`,
 				},
 			},
 		},
+		{
+			description: `if truthiness`,
+			template:    `#if($name)a#end#if($empty)b#end#if($items)c#end#if($none)d#end#if(!$user)e#end#if($noUser)f#end#if($zero)g#end#if($m)h#end#if($missing)i#end#if($name && !$none)j#end`,
+			definedVars: map[string]interface{}{
+				"name": "Bob", "empty": "", "items": []int{1}, "none": []int{}, "user": &bar{}, "noUser": (*bar)(nil),
+				"zero": 0.0, "m": map[string]int{},
+			},
+			expect: "acj",
+		},
+		{
+			description: `if truthiness of pointers and interfaces`,
+			template:    `#if($flag)a#end#if($count)b#end#if($holder.Value)c#end#if($empty.Value)d#end`,
+			definedVars: map[string]interface{}{
+				"flag": new(bool), "count": func() *int { i := 2; return &i }(),
+				"holder": &struct{ Value interface{} }{Value: "x"}, "empty": &struct{ Value interface{} }{},
+			},
+			expect: "bc",
+		},
+		{
+			description: `if truthiness of sized numbers and collections`,
+			template:    `#if($i8)a#end#if($u16)b#end#if($f32)c#end#if($negZero)d#end#if($counts)e#end#if($noCounts)f#end#if($arr)g#end#if($big)h#end`,
+			definedVars: map[string]interface{}{
+				"i8": int8(-1), "u16": uint16(0), "f32": float32(0.5), "negZero": math.Copysign(0, -1),
+				"counts": map[string]int{"a": 1}, "noCounts": map[string]int(nil), "arr": [0]int{}, "big": uint64(1 << 40),
+			},
+			expect: "aceh",
+		},
+		{
+			description: `if zero is true`,
+			template:    `#if($zero)zero#end`,
+			definedVars: map[string]interface{}{"zero": 0},
+			options:     []velty.Option{velty.ZeroIsTrue(true)},
+			expect:      "zero",
+		},
+		{
+			description: `if strict truthiness`,
+			template:    `#if($name)a#end`,
+			definedVars: map[string]interface{}{"name": "Bob"},
+			options:     []velty.Option{velty.StrictTruthiness},
+			expectError: true,
+		},
+		{
+			description: `numeric kinds rendering`,
+			template:    `$r.ID $r.Count $r.Level $r.Price`,
+			definedVars: map[string]interface{}{"r": &reading{ID: -9000000000, Count: 4000000000, Level: -3, Price: 2.25}},
+			expect:      "-9000000000 4000000000 -3 2.25",
+		},
+		{
+			description: `numeric kinds arithmetic`,
+			template:    `#set($next = $r.ID + 1)#set($total = $r.Count * $r.Level)#set($cost = $r.Price * $r.Count)$next $total $cost`,
+			definedVars: map[string]interface{}{"r": &reading{ID: 41, Count: 3, Level: 2, Price: 1.5}},
+			expect:      "42 6 4.5",
+		},
+		{
+			description: `numeric kinds mixed comparison`,
+			template:    `#if($r.ID > $r.Count)a#end#if($r.Level == 2)b#end#if($r.Price < $r.Level)c#end#if($r.Count != 3)d#end`,
+			definedVars: map[string]interface{}{"r": &reading{ID: 41, Count: 3, Level: 2, Price: 1.5}},
+			expect:      "abc",
+		},
+		{
+			description: `numeric pointers operands`,
+			template:    `#set($limit = $r.Limit + 1)#set($ratio = $r.Ratio * 2)$limit $ratio #if($r.Limit >= $r.Count)a#end#if($r.Ratio < 1)b#end`,
+			definedVars: map[string]interface{}{"r": &reading{Count: 3, Limit: intPtr(5), Ratio: float64Ptr(0.25)}},
+			expect:      "6 0.5 ab",
+		},
+		{
+			description: `numeric nil pointers operands`,
+			template:    `#set($limit = $r.Limit + 1)$limit #if($r.Limit > 0)a#else b#end`,
+			definedVars: map[string]interface{}{"r": &reading{}},
+			expect:      "1  b",
+		},
+		{
+			description: `unsigned comparison above max int64`,
+			template:    `#if($r.Total > 0)pos#else neg#end #if($r.Total > $r.Count)a#end#if(-1 < $r.Count)b#end#if($r.Level < $r.Total)c#end#if($r.Total == 0)d#end`,
+			definedVars: map[string]interface{}{"r": &reading{Total: 1<<63 + 5, Count: 3, Level: -3}},
+			expect:      "pos abc",
+		},
+		{
+			description: `unsigned arithmetic above max int64`,
+			template:    `#set($sum = $r.Total + $r.Count)#set($half = $r.Total / 2)$sum $half`,
+			definedVars: map[string]interface{}{"r": &reading{Total: 1<<63 + 5, Count: 3}},
+			expect:      "9223372036854775816 4611686018427387906",
+		},
+		{
+			description: `unsigned pointers operands`,
+			template:    `#set($quota = $r.Quota + 2)$quota #if($r.Quota > 1)a#else b#end#if($r.Quota != $r.Total)c#end`,
+			definedVars: map[string]interface{}{"r": &reading{Total: 1}},
+			expect:      "2  bc",
+		},
+		{
+			description: `numeric kinds assignment`,
+			template:    `#set($r.ID = $r.ID * 2)#set($r.Price = 3.5)#set($r.Count = 7)$r.ID $r.Price $r.Count`,
+			definedVars: map[string]interface{}{"r": &reading{ID: 21}},
+			expect:      "42 3.5 7",
+		},
+		{
+			description: `numeric kinds range bound`,
+			template:    `#foreach($i in [1..$r.Level])$i#end`,
+			definedVars: map[string]interface{}{"r": &reading{Level: 3}},
+			expect:      "123",
+		},
 		{
 			description: `if struct not nil`,
 			template:    `#if($foo)foo was set#else unset foo#end`,
@@ -1283,6 +2174,248 @@ $lastColumnName`,
 			},
 			expect: "my testtrue",
 		},
+		{
+			description: "macro",
+			template:    `#macro(greet $name $title)Hello $title $name! #end#greet("Smith" "Mr")#greet($user.Name, "Ms")`,
+			definedVars: map[string]interface{}{
+				"user": &bar{Name: "Doe"},
+			},
+			expect: "Hello Mr Smith! Hello Ms Doe! ",
+		},
+		{
+			description: "macro defined after call",
+			template:    `#sum(1 2) #sum(10 5)#macro(sum $x $y)$x + $y = #set($z = $x + $y)$z#end`,
+			expect:      "1 + 2 = 3 10 + 5 = 15",
+		},
+		{
+			description: "macro param shadows variable",
+			template:    `#macro(show $name)[$name]#end#show("inner")$name`,
+			definedVars: map[string]interface{}{
+				"name": "outer",
+			},
+			expect: "[inner]outer",
+		},
+		{
+			description: "macro missing argument",
+			template:    `#macro(show $a $b)$a-$b#end#show("x")`,
+			definedVars: map[string]interface{}{
+				"b": "outer",
+			},
+			expect: "x-$b",
+		},
+		{
+			description: "undefined macro",
+			template:    `#undefined($x)`,
+			expect:      "#undefined($x)",
+		},
+		{
+			description: "too many macro arguments",
+			template:    `#macro(show $a)$a#end#show(1 2)`,
+			expectError: true,
+		},
+		{
+			description: "block macro",
+			template:    `#macro(tag $name)<$name>$bodyContent</$name>#end#@tag("b")#foreach($item in $items)$item.Name #end#end`,
+			definedVars: map[string]interface{}{
+				"items": []*bar{{Name: "a"}, {Name: "b"}},
+			},
+			expect: "<b>a b </b>",
+		},
+		{
+			description: "nested block macro",
+			template:    `#macro(tag $name)<$name>$bodyContent</$name>#end#@tag("p")#@tag("i")$value#end#end`,
+			definedVars: map[string]interface{}{
+				"value": "abc",
+			},
+			expect: "<p><i>abc</i></p>",
+		},
+		{
+			description: "macro in evaluate",
+			template:    `#macro(twice $v)$v$v#end#evaluate($tmpl)`,
+			definedVars: map[string]interface{}{
+				"tmpl": `#twice("ab")`,
+			},
+			expect: "abab",
+		},
+		{
+			description: "parse",
+			template:    `#parse("header.vm")$title #greet("Doe")`,
+			definedVars: map[string]interface{}{
+				"name": "abc",
+			},
+			options: []velty.Option{velty.NewFSLoader(templates)},
+			expect:  "[abc]Title Hello Doe",
+		},
+		{
+			description: "include",
+			template:    `#include("/static.txt")`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "static $name",
+		},
+		{
+			description: "dynamic parse and include",
+			template:    `#parse($header)#include($static)`,
+			definedVars: map[string]interface{}{
+				"name":   "abc",
+				"header": "header.vm",
+				"static": "static.txt",
+			},
+			options: []velty.Option{velty.NewFSLoader(templates)},
+			expect:  "[abc]static $name",
+		},
+		{
+			description: "parse cycle",
+			template:    `#parse("cycle.vm")`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expectError: true,
+		},
+		{
+			description: "dynamic parse cycle",
+			template:    `#parse($self)`,
+			definedVars: map[string]interface{}{
+				"self": "self.vm",
+			},
+			options:           []velty.Option{velty.NewFSLoader(templates)},
+			expectTemplateErr: true,
+			expect:            "self",
+		},
+		{
+			description: "dynamic parse cycle with cache",
+			template:    `#parse($self)`,
+			definedVars: map[string]interface{}{
+				"self": "self.vm",
+			},
+			options:           []velty.Option{velty.NewFSLoader(templates), velty.CacheSize(10)},
+			expectTemplateErr: true,
+			expect:            "self",
+		},
+		{
+			description: "dynamic parse cycle through cached template",
+			template:    `#parse($a)|#parse($b)`,
+			definedVars: map[string]interface{}{
+				"a": "a.vm",
+				"b": "b.vm",
+			},
+			options:           []velty.Option{velty.NewFSLoader(templates), velty.CacheSize(10)},
+			expectTemplateErr: true,
+			expect:            "ab|ba",
+		},
+		{
+			description: "extends",
+			template:    "#extends(\"layout.vm\")\n#set($title = \"Home\")\n#block(\"title\")$title#end\n#block(\"content\")Hello $name#end\n",
+			definedVars: map[string]interface{}{
+				"name": "Bob",
+			},
+			options: []velty.Option{velty.NewFSLoader(templates)},
+			expect:  "<title>Home</title><body>Hello Bob</body>",
+		},
+		{
+			description: "extends keeps layout default block",
+			template:    `#extends("layout.vm")#block("content")Hello#end`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "<title>Default</title><body>Hello</body>",
+		},
+		{
+			description: "extends multi level layout",
+			template:    `#extends("section.vm")#block("main")page#end`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "<title>Default</title><body><main>page</main></body>",
+		},
+		{
+			description: "layout without extends",
+			template:    `#parse("layout.vm")`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "<title>Default</title><body></body>",
+		},
+		{
+			description: "extends with output outside of block",
+			template:    `#extends("layout.vm")Hello $name #if(true)X#end#block("content")body#end`,
+			definedVars: map[string]interface{}{"name": "Bob"},
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expectError: true,
+		},
+		{
+			description: "extends with macro and param outside of block",
+			template:    "#extends(\"layout.vm\")\n#param($name string)\n#macro(hello $n)Hello $n#end\n#block(\"content\")#hello($name)#end",
+			definedVars: map[string]interface{}{"name": "Bob"},
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect:      "<title>Default</title><body>Hello Bob</body>",
+		},
+		{
+			description: "extends cycle",
+			template:    `#extends("extends.vm")`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expectError: true,
+		},
+		{
+			description: "nested extends",
+			template:    `#if(true)#extends("layout.vm")#end`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expectError: true,
+		},
+		{
+			description: "parse without loader",
+			template:    `#parse("header.vm")`,
+			expectError: true,
+		},
+		{
+			description: "parse missing template",
+			template:    `#parse("missing.vm")`,
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expectError: true,
+		},
+		{
+			description: "unresolved literal references",
+			template:    `$missing $foo.Missing $!missing $!{missing.Name} $!foo.Name`,
+			definedVars: map[string]interface{}{
+				"foo": &bar{Name: "abc"},
+			},
+			expect: "$missing $foo.Missing   abc",
+		},
+		{
+			description: "unresolved empty references",
+			template:    `[$missing][${foo.Missing}][$!missing]$foo.Name`,
+			definedVars: map[string]interface{}{
+				"foo": &bar{Name: "abc"},
+			},
+			options: []velty.Option{velty.EmptyReferences},
+			expect:  "[][][]abc",
+		},
+		{
+			description: "unresolved strict reference",
+			template:    `$foo.Name $missing`,
+			definedVars: map[string]interface{}{
+				"foo": &bar{Name: "abc"},
+			},
+			options:     []velty.Option{velty.StrictReferences},
+			expectError: true,
+		},
+		{
+			description: "unresolved strict field reference",
+			template:    `$foo.Missing`,
+			definedVars: map[string]interface{}{
+				"foo": &bar{Name: "abc"},
+			},
+			options:     []velty.Option{velty.StrictReferences},
+			expectError: true,
+		},
+		{
+			description: "unresolved strict expression reference",
+			template:    `#foreach($item in $missing)$item#end`,
+			options:     []velty.Option{velty.StrictReferences},
+			expectError: true,
+		},
+		{
+			description: "strict quiet and assigned references",
+			template:    `#set($x = "abc")$x$!missing`,
+			options:     []velty.Option{velty.StrictReferences},
+			expect:      "abc",
+		},
+		{
+			description: "recursive macro",
+			template:    `#macro(loop $a)#loop($a)#end#loop(1)`,
+			expectError: true,
+		},
 	}
 
 	//for i, testCase := range testCases[:len(testCases)-1] {
@@ -1498,6 +2631,223 @@ func Test_ForEach_Issue(t *testing.T) {
 
 }
 
+func TestExecution_ExecTo(t *testing.T) {
+	testCases := []struct {
+		description string
+		bufferSize  int
+		failWriter  bool
+	}{
+		{description: "small flush buffer", bufferSize: 8},
+		{description: "empty flush buffer", bufferSize: 0},
+		{description: "large flush buffer", bufferSize: 8192},
+		{description: "writer error", bufferSize: 8, failWriter: true},
+	}
+
+	template := `#foreach($item in $items)[$item.Name:$item.UpperCase()] #end$count $ratio $flag`
+	items := []*bar{{Name: "first"}, {Name: "second element with a very long name"}, {Name: "x"}}
+	expect := "[first:FIRST] [second element with a very long name:SECOND ELEMENT WITH A VERY LONG NAME] [x:X] 3 0.5 true"
+
+	for _, testCase := range testCases {
+		planner := velty.New(velty.BufferSize(testCase.bufferSize))
+		assert.Nil(t, planner.DefineVariable("items", items), testCase.description)
+		assert.Nil(t, planner.DefineVariable("count", 0), testCase.description)
+		assert.Nil(t, planner.DefineVariable("ratio", 0.0), testCase.description)
+		assert.Nil(t, planner.DefineVariable("flag", false), testCase.description)
+		exec, newState, err := planner.Compile([]byte(template))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+
+		state := newState()
+		assert.Nil(t, state.SetValue("items", items), testCase.description)
+		assert.Nil(t, state.SetValue("count", 3), testCase.description)
+		assert.Nil(t, state.SetValue("ratio", 0.5), testCase.description)
+		assert.Nil(t, state.SetValue("flag", true), testCase.description)
+
+		if testCase.failWriter {
+			assert.NotNil(t, exec.ExecTo(state, &failingWriter{}), testCase.description)
+			continue
+		}
+
+		writer := &strings.Builder{}
+		assert.Nil(t, exec.ExecTo(state, writer), testCase.description)
+		assert.Equal(t, expect, writer.String(), testCase.description)
+		assert.Equal(t, "", state.Buffer.String(), testCase.description)
+	}
+}
+
+type failingWriter struct{}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("failed to write %v bytes", len(p))
+}
+
+func TestPlanner_CompileError(t *testing.T) {
+	testCases := []struct {
+		description string
+		template    string
+		options     []velty.Option
+		expect      velty.Error
+	}{
+		{
+			description: "unknown field",
+			template:    "line 1\n  #set($x = $foo.Missing) line 2",
+			options:     []velty.Option{velty.TemplateName("main.vm")},
+			expect: velty.Error{
+				Template:  "main.vm",
+				Line:      2,
+				Column:    3,
+				Directive: "#set($x = $foo.Missing)",
+				Snippet:   "  #set($x = $foo.Missing) line 2\n  ^",
+			},
+		},
+		{
+			description: "unterminated statement",
+			template:    "abc\n#foreach($item in $foo.Names) $item",
+			expect: velty.Error{
+				Line:      2,
+				Column:    1,
+				Directive: "#foreach($item in $foo.Names)",
+				Snippet:   "#foreach($item in $foo.Names) $item\n^",
+			},
+		},
+		{
+			description: "unexpected end",
+			template:    "abc #end",
+			expect: velty.Error{
+				Line:      1,
+				Column:    5,
+				Directive: "#end",
+				Snippet:   "abc #end\n    ^",
+			},
+		},
+		{
+			description: "error in parsed template",
+			template:    "#parse(\"broken.vm\")",
+			options:     []velty.Option{velty.NewFSLoader(templates), velty.StrictReferences},
+			expect: velty.Error{
+				Template:  "broken.vm",
+				Line:      2,
+				Column:    1,
+				Directive: "$foo.Missing",
+				Snippet:   "$foo.Missing\n^",
+			},
+		},
+		{
+			description: "error in extending template block",
+			template:    "#extends(\"layout.vm\")\n#block(\"content\")\n$foo.Missing#end",
+			options:     []velty.Option{velty.NewFSLoader(templates), velty.StrictReferences},
+			expect: velty.Error{
+				Line:      3,
+				Column:    1,
+				Directive: "$foo.Missing",
+				Snippet:   "$foo.Missing#end\n^",
+			},
+		},
+		{
+			description: "output outside of block in extending template",
+			template:    "#extends(\"layout.vm\")\nHello $foo.Name",
+			options:     []velty.Option{velty.NewFSLoader(templates)},
+			expect: velty.Error{
+				Line:      2,
+				Column:    7,
+				Directive: "$foo.Name",
+				Snippet:   "Hello $foo.Name\n      ^",
+			},
+		},
+		{
+			description: "error in macro body",
+			template:    "#macro(show $a)\n\t$a.Missing#end\n#show($foo)",
+			options:     []velty.Option{velty.StrictReferences},
+			expect: velty.Error{
+				Line:      2,
+				Column:    2,
+				Directive: "$a.Missing",
+				Snippet:   "\t$a.Missing#end\n\t^",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		planner := velty.New(testCase.options...)
+		assert.Nil(t, planner.DefineVariable("foo", &bar{}), testCase.description)
+		_, _, err := planner.Compile([]byte(testCase.template))
+		actual := &velty.Error{}
+		if !assert.True(t, errors.As(err, &actual), testCase.description) {
+			continue
+		}
+
+		assert.Equal(t, testCase.expect.Template, actual.Template, testCase.description)
+		assert.Equal(t, testCase.expect.Line, actual.Line, testCase.description)
+		assert.Equal(t, testCase.expect.Column, actual.Column, testCase.description)
+		assert.Equal(t, testCase.expect.Directive, actual.Directive, testCase.description)
+		assert.Equal(t, testCase.expect.Snippet, actual.Snippet, testCase.description)
+	}
+}
+
+func TestPlanner_Inputs(t *testing.T) {
+	planner := velty.New()
+	_, _, err := planner.Compile([]byte(`#param($limit int)#var($filters map[string][]int)#var($since *time.Time)`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var actual []string
+	for _, input := range planner.Inputs() {
+		actual = append(actual, fmt.Sprintf("%v %v %v", input.Name, input.Type.String(), input.Required))
+	}
+	assert.Equal(t, []string{"limit int true", "filters map[string][]int false", "since *time.Time false"}, actual)
+
+	_, _, err = planner.Compile([]byte(`#param($limit int)$limit`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	if assert.Equal(t, 1, len(planner.Inputs())) {
+		assert.Equal(t, "limit", planner.Inputs()[0].Name)
+	}
+}
+
+func TestExecution_Errors(t *testing.T) {
+	template := "ok\n#set($a = $strconv.Atoi(\"abc\"))\n  $errors.RegisterError(\"boom\")\n#set($b = 7 % 0)"
+	planner := velty.New(velty.TemplateName("main.vm"))
+	exec, newState, err := planner.Compile([]byte(template))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	err = exec.Exec(newState())
+	var execErrors est.Errors
+	if !assert.True(t, errors.As(err, &execErrors)) {
+		return
+	}
+
+	expect := []velty.Error{
+		{Template: "main.vm", Line: 2, Column: 11, Directive: `$strconv.Atoi("abc")`},
+		{Template: "main.vm", Line: 3, Column: 3, Directive: `$errors.RegisterError("boom")`},
+		{Template: "main.vm", Line: 4, Column: 1, Directive: `#set($b = 7 % 0)`},
+	}
+	if !assert.Equal(t, len(expect), len(execErrors)) {
+		return
+	}
+
+	for i, expectErr := range expect {
+		actual := &velty.Error{}
+		if !assert.True(t, errors.As(execErrors[i], &actual)) {
+			continue
+		}
+		assert.Equal(t, expectErr.Template, actual.Template)
+		assert.Equal(t, expectErr.Line, actual.Line)
+		assert.Equal(t, expectErr.Column, actual.Column)
+		assert.Equal(t, expectErr.Directive, actual.Directive)
+		assert.NotNil(t, actual.Err)
+	}
+
+	first := &velty.Error{}
+	assert.True(t, errors.As(err, &first))
+	assert.Equal(t, 2, first.Line)
+}
+
 type definedVariable struct {
 	valueType interface{}
 	value     interface{}
@@ -1513,10 +2863,29 @@ type column struct {
 	aString string
 }
 
+func decodeJSON(text string) interface{} {
+	var result interface{}
+	_ = json.Unmarshal([]byte(text), &result)
+	return result
+}
+
 func intPtr(value int) *int {
 	return &value
 }
 
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func intChan(values ...int) <-chan int {
+	result := make(chan int, len(values))
+	for _, value := range values {
+		result <- value
+	}
+	close(result)
+	return result
+}
+
 func boolPtr(aBool bool) *bool {
 	return &aBool
 }
@@ -0,0 +1,35 @@
+package velty
+
+import (
+	"fmt"
+	"github.com/viant/velty/ast"
+	eexpr "github.com/viant/velty/est/expr"
+	"github.com/viant/velty/est/op"
+	"reflect"
+)
+
+var boolType = reflect.TypeOf(true)
+
+//compileCondition compiles expression used as a condition, non bool values are evaluated with Velocity truthiness rules
+func (p *Planner) compileCondition(e ast.Expression) (*op.Expression, error) {
+	cond, err := p.compileExpr(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if cond.Type != nil && cond.Type.Kind() == reflect.Bool {
+		return cond, nil
+	}
+
+	if p.truthiness == StrictTruthiness {
+		if cond.Type == nil {
+			return cond, nil
+		}
+		return nil, fmt.Errorf("condition has to be bool, but had %v", cond.Type.String())
+	}
+
+	return &op.Expression{
+		Type: boolType,
+		New:  eexpr.Truthy(cond, p.zeroIsTrue),
+	}, nil
+}
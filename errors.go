@@ -0,0 +1,8 @@
+package velty
+
+import (
+	"github.com/viant/velty/ast"
+)
+
+//Error represents template parse or compile error with the template name, line, column and source snippet
+type Error = ast.Error
@@ -3,7 +3,9 @@ package est
 import (
 	"github.com/viant/velty/utils"
 	"html"
+	"io"
 	"strconv"
+	"time"
 )
 
 type Buffer struct {
@@ -11,12 +13,14 @@ type Buffer struct {
 	index      int
 	poolSize   int
 	escapeHTML bool
+	timeLayout string
+	writer     io.Writer
+	err        error
 }
 
 func (b *Buffer) AppendByte(bs byte) {
 	if b.index+1 >= len(b.buf) {
-		newBuffer := make([]byte, len(b.buf)+b.poolSize)
-		b.buf = append(b.buf, newBuffer...)
+		b.growIfNeeded(1)
 	}
 	b.buf[b.index] = bs
 	b.index++
@@ -27,6 +31,16 @@ func (b *Buffer) AppendInt(v int) {
 	b.index += utils.AppendInt(b.buf[b.index:], int64(v), 10)
 }
 
+func (b *Buffer) AppendInt64(v int64) {
+	b.growIfNeeded(65)
+	b.index += utils.AppendInt(b.buf[b.index:], v, 10)
+}
+
+func (b *Buffer) AppendUint64(v uint64) {
+	b.growIfNeeded(65)
+	b.index += utils.AppendUint(b.buf[b.index:], v, 10)
+}
+
 func (b *Buffer) AppendBool(v bool) {
 	s := strconv.FormatBool(v)
 	b.AppendString(s)
@@ -37,6 +51,30 @@ func (b *Buffer) AppendFloat(v float64) {
 	b.AppendString(s)
 }
 
+func (b *Buffer) AppendFloat32(v float32) {
+	s := strconv.FormatFloat(float64(v), 'f', -1, 32)
+	b.AppendString(s)
+}
+
+//AppendTime appends time formatted with the buffer time layout, time.RFC3339 by default
+func (b *Buffer) AppendTime(t time.Time) {
+	layout := b.timeLayout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	b.AppendString(t.Format(layout))
+}
+
+//SetTimeLayout sets layout used to render time values
+func (b *Buffer) SetTimeLayout(layout string) {
+	b.timeLayout = layout
+}
+
+//TimeLayout returns layout used to render time values, empty if it was not set
+func (b *Buffer) TimeLayout() string {
+	return b.timeLayout
+}
+
 func (b *Buffer) AppendString(s string) {
 	if !b.escapeHTML {
 		b.AppendStringWithoutEscaping(s)
@@ -51,12 +89,24 @@ func (b *Buffer) AppendStringWithoutEscaping(s string) {
 	if sLen == 0 {
 		return
 	}
+	if b.writer != nil && sLen+b.index >= len(b.buf) {
+		b.Flush()
+		if sLen >= len(b.buf) {
+			b.writeString(s)
+			return
+		}
+	}
+
 	b.growIfNeeded(sLen)
 	copy(b.buf[b.index:], s)
 	b.index += sLen
 }
 
 func (b *Buffer) growIfNeeded(sLen int) {
+	if b.writer != nil && sLen+b.index >= len(b.buf) {
+		b.Flush()
+	}
+
 	if sLen+b.index >= len(b.buf) {
 		size := len(b.buf) + b.poolSize
 		if size < sLen {
@@ -69,6 +119,32 @@ func (b *Buffer) growIfNeeded(sLen int) {
 
 func (b *Buffer) Reset() {
 	b.index = 0
+	b.err = nil
+}
+
+//SetWriter sets writer the buffer is flushed to when full, nil writer restores in memory buffering
+func (b *Buffer) SetWriter(writer io.Writer) {
+	b.writer = writer
+	b.err = nil
+}
+
+//Flush writes buffered data to the writer, after the first write error data is discarded
+func (b *Buffer) Flush() error {
+	if b.writer == nil || b.index == 0 {
+		return b.err
+	}
+
+	if b.err == nil {
+		_, b.err = b.writer.Write(b.buf[:b.index])
+	}
+	b.index = 0
+	return b.err
+}
+
+func (b *Buffer) writeString(s string) {
+	if b.err == nil {
+		_, b.err = io.WriteString(b.writer, s)
+	}
 }
 
 func (b *Buffer) Bytes() []byte {
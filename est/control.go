@@ -1,6 +1,13 @@
 package est
 
-//TODO create const hasBreak, hasContinue
-
 //Control represents execution control flags like uses continue, uses break
 type Control uint8
+
+const (
+	//HasBreak flags #break usage, in the State it represents pending break request
+	HasBreak Control = 1 << iota
+	//HasContinue flags #continue usage, in the State it represents pending continue request
+	HasContinue
+	//HasStop flags #stop usage, in the State it represents pending stop request
+	HasStop
+)
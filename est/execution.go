@@ -1,6 +1,9 @@
 package est
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 type Execution struct {
 	compute      Compute
@@ -22,13 +25,26 @@ func (e *Execution) Exec(stat *State) (err error) {
 	}
 
 	e.compute(stat)
+	stat.Control = 0
 	if len(stat.Errors) > 0 {
-		return fmt.Errorf("error occured while processing template: %w", stat.Errors[0])
+		return fmt.Errorf("error occured while processing template: %w", Errors(stat.Errors))
 	}
 
 	return err
 }
 
+//ExecTo executes template and streams output to the writer, state buffer is used as a flush buffer
+func (e *Execution) ExecTo(state *State, writer io.Writer) error {
+	state.Buffer.SetWriter(writer)
+	defer state.Buffer.SetWriter(nil)
+
+	err := e.Exec(state)
+	if flushErr := state.Buffer.Flush(); err == nil && flushErr != nil {
+		err = fmt.Errorf("error occured while writing template output: %w", flushErr)
+	}
+	return err
+}
+
 func NewExecution(compute Compute) *Execution {
 	return &Execution{compute: compute}
 }
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"github.com/viant/xunsafe/converter"
+	"reflect"
+	"unsafe"
+)
+
+//Alternate creates a compute returning x value unified with unify, or the alternate value if x is null or false according to Velocity truthiness,
+//i.e. ${name|'anonymous'}. Without the alternate value, null x yields the zero value.
+func Alternate(x *op.Expression, unify converter.UnifyFn, alternate *op.Expression, zeroIsTrue bool) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		xOperand, err := x.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+
+		rType := x.Type
+		if alternate == nil {
+			return func(state *est.State) unsafe.Pointer {
+				if ptr := xOperand.Exec(state); ptr != nil {
+					return ptr
+				}
+				return unsafe.Pointer(reflect.New(rType).Pointer()) //new zero value, as the caller can modify it
+			}, nil
+		}
+
+		alternateOperand, err := alternate.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+
+		isTrue := TruthFunc(rType, zeroIsTrue)
+		return func(state *est.State) unsafe.Pointer {
+			ptr := xOperand.Exec(state)
+			if ptr == nil || !isTrue(ptr) {
+				return alternateOperand.Exec(state)
+			}
+
+			if unify != nil {
+				ptr, _ = unify(ptr)
+			}
+			return ptr
+		}, nil
+	}
+}
+
+//Box creates unifier converting rType value to interface{} holding it
+func Box(rType reflect.Type) converter.UnifyFn {
+	return func(pointer unsafe.Pointer) (unsafe.Pointer, error) {
+		value := reflect.NewAt(rType, pointer).Elem().Interface()
+		return unsafe.Pointer(&value), nil
+	}
+}
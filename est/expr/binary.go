@@ -1,12 +1,14 @@
 package expr
 
 import (
+	"errors"
 	"fmt"
 	"github.com/viant/velty/ast"
 	"github.com/viant/velty/est"
 	"github.com/viant/velty/est/op"
 	"github.com/viant/velty/est/stmt"
 	"reflect"
+	"unsafe"
 )
 
 type binaryExpr struct {
@@ -15,6 +17,15 @@ type binaryExpr struct {
 	z *op.Operand
 }
 
+var errDivisionByZero = errors.New("integer division by zero")
+
+//divisionByZero reports integer division or modulo by zero, the result is zero value
+func (b *binaryExpr) divisionByZero(state *est.State, z unsafe.Pointer) unsafe.Pointer {
+	state.AddError(b.z.Sel.Location.WithError(errDivisionByZero))
+	reflect.NewAt(b.z.Type, z).Elem().Set(reflect.Zero(b.z.Type))
+	return z
+}
+
 func Binary(token ast.Token, exprs ...*op.Expression) (est.New, error) {
 	return func(control est.Control) (est.Compute, error) {
 		if exprs[0].Type == nil {
@@ -34,6 +45,25 @@ func Binary(token ast.Token, exprs ...*op.Expression) (est.New, error) {
 			rType = exprs[1].Type
 		}
 
+		if IsTime(exprs[0].Type) && IsTime(exprs[1].Type) {
+			return computeBinaryTime(token, binary)
+		}
+
+		if numeric, ok := NumericType(exprs[0].Type, exprs[1].Type); ok {
+			if isComparison(token) && IsMixedSign(exprs[0].Type, exprs[1].Type) {
+				return computeMixedSign(token, binary, IsUnsigned(exprs[0].Type))
+			}
+
+			if numeric == uint64Type {
+				return computeBinaryUint(token, binary)
+			}
+
+			if isComparison(token) && (exprs[0].Type.Kind() == reflect.Ptr || exprs[1].Type.Kind() == reflect.Ptr) {
+				return computePtr(reflect.PtrTo(numeric), token, binary, indirect)
+			}
+			rType = numeric
+		}
+
 		switch rType.Kind() {
 		case reflect.Int:
 			return computeBinaryInt(token, binary, indirect)
@@ -77,14 +77,12 @@ func (b *binaryExpr) directBoolNeq(state *est.State) unsafe.Pointer {
 	return z
 }
 
+//indirectBoolAnd evaluates y only if x is true
 func (b *binaryExpr) indirectBoolAnd(state *est.State) unsafe.Pointer {
-	x := b.x.Exec(state)
-	y := b.y.Exec(state)
-	z := est.FalseValuePtr
-	if *(*bool)(x) && *(*bool)(y) {
-		z = est.TrueValuePtr
+	if *(*bool)(b.x.Exec(state)) && *(*bool)(b.y.Exec(state)) {
+		return est.TrueValuePtr
 	}
-	return z
+	return est.FalseValuePtr
 }
 
 func (b *binaryExpr) directBoolAnd(state *est.State) unsafe.Pointer {
@@ -97,14 +95,12 @@ func (b *binaryExpr) directBoolAnd(state *est.State) unsafe.Pointer {
 	return z
 }
 
+//indirectBoolOr evaluates y only if x is false
 func (b *binaryExpr) indirectBoolOr(state *est.State) unsafe.Pointer {
-	x := b.x.Exec(state)
-	y := b.y.Exec(state)
-	z := est.FalseValuePtr
-	if *(*bool)(x) || *(*bool)(y) {
-		z = est.TrueValuePtr
+	if *(*bool)(b.x.Exec(state)) || *(*bool)(b.y.Exec(state)) {
+		return est.TrueValuePtr
 	}
-	return z
+	return est.FalseValuePtr
 }
 
 func (b *binaryExpr) directBoolOr(state *est.State) unsafe.Pointer {
@@ -0,0 +1,112 @@
+package expr
+
+import (
+	"fmt"
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"reflect"
+	"unsafe"
+)
+
+//List creates a compute building new slice out of the elements on every execution
+func List(elements []*op.Expression, result *op.Expression) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		operands, err := elementOperands(control, elements)
+		if err != nil {
+			return nil, err
+		}
+
+		z, err := result.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+
+		sliceType := result.Type
+		return func(state *est.State) unsafe.Pointer {
+			aSlice := reflect.MakeSlice(sliceType, len(operands), len(operands))
+			for i, operand := range operands {
+				if value, ok := elementValue(state, operand); ok {
+					aSlice.Index(i).Set(value)
+				}
+			}
+
+			ptr := z.Pointer(state)
+			reflect.NewAt(sliceType, ptr).Elem().Set(aSlice)
+			return ptr
+		}, nil
+	}
+}
+
+//Map creates a compute building new map out of the keys and values on every execution
+func Map(keys, values []*op.Expression, result *op.Expression) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		keyOperands, err := elementOperands(control, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		valueOperands, err := elementOperands(control, values)
+		if err != nil {
+			return nil, err
+		}
+
+		z, err := result.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+
+		mapType := result.Type
+		keyType, valueType := mapType.Key(), mapType.Elem()
+		return func(state *est.State) unsafe.Pointer {
+			aMap := reflect.MakeMapWithSize(mapType, len(keyOperands))
+			for i, keyOperand := range keyOperands {
+				key, ok := elementValue(state, keyOperand)
+				if !ok {
+					continue
+				}
+
+				if !key.Type().AssignableTo(keyType) {
+					key = reflect.ValueOf(fmt.Sprint(key.Interface()))
+				}
+
+				value, ok := elementValue(state, valueOperands[i])
+				if !ok {
+					value = reflect.Zero(valueType)
+				}
+				aMap.SetMapIndex(key, value)
+			}
+
+			ptr := z.Pointer(state)
+			reflect.NewAt(mapType, ptr).Elem().Set(aMap)
+			return ptr
+		}, nil
+	}
+}
+
+func elementOperands(control est.Control, elements []*op.Expression) ([]*op.Operand, error) {
+	result := make([]*op.Operand, len(elements))
+	var err error
+	for i, element := range elements {
+		if element.Type == nil {
+			continue
+		}
+
+		if result[i], err = element.Operand(control); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+//elementValue returns operand value, or false if operand was not resolved
+func elementValue(state *est.State, operand *op.Operand) (reflect.Value, bool) {
+	if operand == nil {
+		return reflect.Value{}, false
+	}
+
+	ptr := operand.Exec(state)
+	if ptr == nil {
+		return reflect.Value{}, false
+	}
+	return reflect.NewAt(operand.Type, ptr).Elem(), true
+}
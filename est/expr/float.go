@@ -3,6 +3,7 @@ package expr
 import (
 	"github.com/viant/velty/ast"
 	"github.com/viant/velty/est"
+	"math"
 	"unsafe"
 )
 
@@ -31,6 +32,11 @@ func computeBinaryFloat(token ast.Token, binary *binaryExpr, indirect bool) (est
 			return binary.indirectFloatMul, nil
 		}
 		return binary.directFloatMul, nil
+	case ast.MOD:
+		if indirect {
+			return binary.indirectFloatMod, nil
+		}
+		return binary.directFloatMod, nil
 	case ast.EQ:
 		if indirect {
 			return binary.indirectFloatEq, nil
@@ -83,6 +89,22 @@ func (b *binaryExpr) directFloatQuo(state *est.State) unsafe.Pointer {
 	return z
 }
 
+func (b *binaryExpr) indirectFloatMod(state *est.State) unsafe.Pointer {
+	x := b.x.Exec(state)
+	y := b.y.Exec(state)
+	z := b.z.Pointer(state)
+	*(*float64)(z) = math.Mod(*(*float64)(x), *(*float64)(y))
+	return z
+}
+
+func (b *binaryExpr) directFloatMod(state *est.State) unsafe.Pointer {
+	x := b.x.Pointer(state)
+	y := b.y.Pointer(state)
+	z := b.z.Pointer(state)
+	*(*float64)(z) = math.Mod(*(*float64)(x), *(*float64)(y))
+	return z
+}
+
 func (b *binaryExpr) indirectFloatAdd(state *est.State) unsafe.Pointer {
 	x := b.x.Exec(state)
 	y := b.y.Exec(state)
@@ -33,6 +33,12 @@ func computeBinaryInt(token ast.Token, binary *binaryExpr, indirect bool) (est.C
 		}
 
 		return binary.directIntMul, nil
+	case ast.MOD:
+		if indirect {
+			return binary.indirectIntMod, nil
+		}
+
+		return binary.directIntMod, nil
 	case ast.NEQ:
 		if indirect {
 			return binary.indirectIntNeq, nil
@@ -77,6 +83,9 @@ func (b *binaryExpr) indirectIntQuo(state *est.State) unsafe.Pointer {
 	x := b.x.Exec(state)
 	y := b.y.Exec(state)
 	z := b.z.Pointer(state)
+	if *(*int)(y) == 0 {
+		return b.divisionByZero(state, z)
+	}
 	*(*int)(z) = *(*int)(x) / *(*int)(y)
 	return z
 }
@@ -85,10 +94,35 @@ func (b *binaryExpr) directIntQuo(state *est.State) unsafe.Pointer {
 	x := b.x.Pointer(state)
 	y := b.y.Pointer(state)
 	z := b.z.Pointer(state)
+	if *(*int)(y) == 0 {
+		return b.divisionByZero(state, z)
+	}
 	*(*int)(z) = *(*int)(x) / *(*int)(y)
 	return z
 }
 
+func (b *binaryExpr) indirectIntMod(state *est.State) unsafe.Pointer {
+	x := b.x.Exec(state)
+	y := b.y.Exec(state)
+	z := b.z.Pointer(state)
+	if *(*int)(y) == 0 {
+		return b.divisionByZero(state, z)
+	}
+	*(*int)(z) = *(*int)(x) % *(*int)(y)
+	return z
+}
+
+func (b *binaryExpr) directIntMod(state *est.State) unsafe.Pointer {
+	x := b.x.Pointer(state)
+	y := b.y.Pointer(state)
+	z := b.z.Pointer(state)
+	if *(*int)(y) == 0 {
+		return b.divisionByZero(state, z)
+	}
+	*(*int)(z) = *(*int)(x) % *(*int)(y)
+	return z
+}
+
 func (b *binaryExpr) indirectIntAdd(state *est.State) unsafe.Pointer {
 	x := b.x.Exec(state)
 	y := b.y.Exec(state)
@@ -0,0 +1,194 @@
+package expr
+
+import (
+	"github.com/viant/velty/ast"
+	"github.com/viant/xunsafe"
+	"github.com/viant/xunsafe/converter"
+	"reflect"
+	"unsafe"
+)
+
+var (
+	intType     = reflect.TypeOf(0)
+	uint64Type  = reflect.TypeOf(uint64(0))
+	float64Type = reflect.TypeOf(0.0)
+)
+
+//IsNumeric returns true if rType is any int, uint or float kind, or a pointer to it
+func IsNumeric(rType reflect.Type) bool {
+	if rType == nil {
+		return false
+	}
+
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+
+	switch rType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+//NumericType returns type both numeric operands are widened to: float64 if any of them is float, uint64 if both are unsigned, int otherwise
+func NumericType(x, y reflect.Type) (reflect.Type, bool) {
+	if !IsNumeric(x) || !IsNumeric(y) {
+		return nil, false
+	}
+
+	if isFloat(x) || isFloat(y) {
+		return float64Type, true
+	}
+
+	if IsUnsigned(x) && IsUnsigned(y) {
+		return uint64Type, true
+	}
+	return intType, true
+}
+
+//IsMixedSign returns true if one of the integer operands is signed and the other one unsigned
+func IsMixedSign(x, y reflect.Type) bool {
+	if !IsNumeric(x) || !IsNumeric(y) || isFloat(x) || isFloat(y) {
+		return false
+	}
+	return IsUnsigned(x) != IsUnsigned(y)
+}
+
+func isFloat(rType reflect.Type) bool {
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	return rType.Kind() == reflect.Float32 || rType.Kind() == reflect.Float64
+}
+
+//IsUnsigned returns true if rType is any uint kind, or a pointer to it
+func IsUnsigned(rType reflect.Type) bool {
+	if rType == nil {
+		return false
+	}
+
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+
+	switch rType.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+//Widen creates unifiers converting numeric operands of any kind, or pointers to them, to the common NumericType.
+//Arithmetic treats nil pointer operand as zero, comparisons keep it nil.
+//Comparison of signed and unsigned operands widens them to int and uint64 respectively, and compares them by sign first.
+func Widen(token ast.Token, x, y reflect.Type) (*converter.Unified, bool, error) {
+	rType, ok := NumericType(x, y)
+	if !ok {
+		return nil, false, nil
+	}
+
+	nilAsZero := !isComparison(token)
+	xType, yType := rType, rType
+	if !nilAsZero && IsMixedSign(x, y) {
+		xType, yType = signedType(x), signedType(y)
+	}
+
+	xUnify, err := widenFn(x, xType, nilAsZero)
+	if err != nil {
+		return nil, false, err
+	}
+
+	yUnify, err := widenFn(y, yType, nilAsZero)
+	if err != nil {
+		return nil, false, err
+	}
+	return &converter.Unified{X: xUnify, Y: yUnify, RType: rType}, true, nil
+}
+
+func signedType(rType reflect.Type) reflect.Type {
+	if IsUnsigned(rType) {
+		return uint64Type
+	}
+	return intType
+}
+
+func widenFn(from, to reflect.Type, nilAsZero bool) (converter.UnifyFn, error) {
+	if to == uint64Type {
+		return widenUint(from, nilAsZero), nil
+	}
+
+	unified, err := converter.Unify(to, from)
+	if err != nil {
+		return nil, err
+	}
+
+	unify := unified.Y
+	if unify == nil || !nilAsZero || from.Kind() != reflect.Ptr {
+		return unify, nil
+	}
+
+	zeroInt, zeroFloat := 0, 0.0
+	zero := unsafe.Pointer(&zeroInt)
+	if to.Kind() == reflect.Float64 {
+		zero = unsafe.Pointer(&zeroFloat)
+	}
+
+	return func(pointer unsafe.Pointer) (unsafe.Pointer, error) {
+		result, err := unify(pointer)
+		if result == nil && err == nil {
+			return zero, nil
+		}
+		return result, err
+	}, nil
+}
+
+func isComparison(token ast.Token) bool {
+	switch token {
+	case ast.EQ, ast.NEQ, ast.GTR, ast.GTE, ast.LSS, ast.LEQ:
+		return true
+	}
+	return false
+}
+
+//widenUint creates unifier converting unsigned integer of any kind, or pointer to it, to uint64
+func widenUint(from reflect.Type, nilAsZero bool) converter.UnifyFn {
+	isPtr := from.Kind() == reflect.Ptr
+	if isPtr {
+		from = from.Elem()
+	}
+
+	var read func(pointer unsafe.Pointer) uint64
+	switch from.Kind() {
+	case reflect.Uint:
+		read = func(pointer unsafe.Pointer) uint64 { return uint64(*(*uint)(pointer)) }
+	case reflect.Uint8:
+		read = func(pointer unsafe.Pointer) uint64 { return uint64(*(*uint8)(pointer)) }
+	case reflect.Uint16:
+		read = func(pointer unsafe.Pointer) uint64 { return uint64(*(*uint16)(pointer)) }
+	case reflect.Uint32:
+		read = func(pointer unsafe.Pointer) uint64 { return uint64(*(*uint32)(pointer)) }
+	default:
+		if !isPtr {
+			return nil
+		}
+		read = func(pointer unsafe.Pointer) uint64 { return *(*uint64)(pointer) }
+	}
+
+	return func(pointer unsafe.Pointer) (unsafe.Pointer, error) {
+		if isPtr {
+			if pointer = xunsafe.DerefPointer(pointer); pointer == nil {
+				if !nilAsZero {
+					return nil, nil
+				}
+				zero := uint64(0)
+				return unsafe.Pointer(&zero), nil
+			}
+		}
+
+		value := read(pointer)
+		return unsafe.Pointer(&value), nil
+	}
+}
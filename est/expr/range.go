@@ -0,0 +1,36 @@
+package expr
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"github.com/viant/velty/est/stmt"
+	"unsafe"
+)
+
+//Range creates a compute building []int out of the range bounds on every execution
+func Range(from, to *op.Expression, inclusive bool, result *op.Expression) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		operands, err := op.Expressions{from, to, result}.Operands(control, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(state *est.State) unsafe.Pointer {
+			begin := *(*int)(operands[op.X].Exec(state))
+			end := *(*int)(operands[op.Y].Exec(state))
+			step := stmt.RangeStep(begin, end)
+			if inclusive {
+				end += step
+			}
+
+			aSlice := make([]int, 0, (end-begin)*step)
+			for i := begin; i != end; i += step {
+				aSlice = append(aSlice, i)
+			}
+
+			ptr := operands[op.Z].Pointer(state)
+			*(*[]int)(ptr) = aSlice
+			return ptr
+		}, nil
+	}
+}
@@ -23,6 +23,8 @@ func computeBinaryString(token ast.Token, binary *binaryExpr, indirect bool) (es
 			return binary.indirectStringNeq, nil
 		}
 		return binary.directStringNeq, nil
+	case ast.GTR, ast.GTE, ast.LSS, ast.LEQ:
+		return binary.stringComparison(token, indirect), nil
 	}
 	return nil, errorUnsupported(token, "string")
 }
@@ -90,3 +92,32 @@ func (b *binaryExpr) directStringNeq(state *est.State) unsafe.Pointer {
 
 	return z
 }
+
+//stringComparison returns lexicographical comparison compute
+func (b *binaryExpr) stringComparison(token ast.Token, indirect bool) est.Compute {
+	var compare func(x, y string) bool
+	switch token {
+	case ast.GTR:
+		compare = func(x, y string) bool { return x > y }
+	case ast.GTE:
+		compare = func(x, y string) bool { return x >= y }
+	case ast.LSS:
+		compare = func(x, y string) bool { return x < y }
+	default:
+		compare = func(x, y string) bool { return x <= y }
+	}
+
+	return func(state *est.State) unsafe.Pointer {
+		var x, y unsafe.Pointer
+		if indirect {
+			x, y = b.x.Exec(state), b.y.Exec(state)
+		} else {
+			x, y = b.x.Pointer(state), b.y.Pointer(state)
+		}
+
+		if compare(*(*string)(x), *(*string)(y)) {
+			return est.TrueValuePtr
+		}
+		return est.FalseValuePtr
+	}
+}
@@ -0,0 +1,25 @@
+package expr
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"unsafe"
+)
+
+//Ternary creates a compute evaluating only the operand selected by the condition
+func Ternary(cond, x, y *op.Expression) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		operands, err := op.Expressions{cond, x, y}.Operands(control, false)
+		if err != nil {
+			return nil, err
+		}
+
+		condOperand, xOperand, yOperand := operands[0], operands[1], operands[2]
+		return func(state *est.State) unsafe.Pointer {
+			if *(*bool)(condOperand.Exec(state)) {
+				return xOperand.Exec(state)
+			}
+			return yOperand.Exec(state)
+		}, nil
+	}
+}
@@ -0,0 +1,74 @@
+package expr
+
+import (
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/est"
+	"github.com/viant/xunsafe"
+	"github.com/viant/xunsafe/converter"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//IsTime returns true if rType is time.Time or *time.Time
+func IsTime(rType reflect.Type) bool {
+	if rType == nil {
+		return false
+	}
+
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	return rType == timeType
+}
+
+//UnifyTime creates unifiers dereferencing *time.Time operands, nil pointer is unified to zero time
+func UnifyTime(x, y reflect.Type) (*converter.Unified, bool) {
+	if !IsTime(x) || !IsTime(y) {
+		return nil, false
+	}
+	return &converter.Unified{X: derefTime(x), Y: derefTime(y), RType: timeType}, true
+}
+
+func derefTime(rType reflect.Type) converter.UnifyFn {
+	if rType.Kind() != reflect.Ptr {
+		return nil
+	}
+
+	zero := unsafe.Pointer(&time.Time{})
+	return func(pointer unsafe.Pointer) (unsafe.Pointer, error) {
+		if pointer = xunsafe.DerefPointer(pointer); pointer == nil {
+			return zero, nil
+		}
+		return pointer, nil
+	}
+}
+
+func computeBinaryTime(token ast.Token, binary *binaryExpr) (est.Compute, error) {
+	var compare func(x, y *time.Time) bool
+	switch token {
+	case ast.EQ:
+		compare = func(x, y *time.Time) bool { return x.Equal(*y) }
+	case ast.NEQ:
+		compare = func(x, y *time.Time) bool { return !x.Equal(*y) }
+	case ast.LSS:
+		compare = func(x, y *time.Time) bool { return x.Before(*y) }
+	case ast.LEQ:
+		compare = func(x, y *time.Time) bool { return !x.After(*y) }
+	case ast.GTR:
+		compare = func(x, y *time.Time) bool { return x.After(*y) }
+	case ast.GTE:
+		compare = func(x, y *time.Time) bool { return !x.Before(*y) }
+	default:
+		return nil, errorUnsupported(token, "time.Time")
+	}
+
+	return func(state *est.State) unsafe.Pointer {
+		if compare((*time.Time)(binary.x.Exec(state)), (*time.Time)(binary.y.Exec(state))) {
+			return est.TrueValuePtr
+		}
+		return est.FalseValuePtr
+	}, nil
+}
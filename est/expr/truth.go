@@ -0,0 +1,113 @@
+package expr
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"reflect"
+	"unsafe"
+)
+
+//Truthy creates a compute evaluating Velocity truthiness of the expression value:
+//null, false, empty string, empty collection and zero number (unless zeroIsTrue) are false, any other value is true
+func Truthy(x *op.Expression, zeroIsTrue bool) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		if x.Type == nil {
+			return func(state *est.State) unsafe.Pointer {
+				return est.FalseValuePtr
+			}, nil
+		}
+
+		operand, err := x.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+
+		isTrue := TruthFunc(x.Type, zeroIsTrue)
+		return func(state *est.State) unsafe.Pointer {
+			ptr := operand.Exec(state)
+			if ptr == nil || !isTrue(ptr) {
+				return est.FalseValuePtr
+			}
+			return est.TrueValuePtr
+		}, nil
+	}
+}
+
+//TruthFunc returns function evaluating Velocity truthiness of the rType value pointer
+func TruthFunc(rType reflect.Type, zeroIsTrue bool) func(ptr unsafe.Pointer) bool {
+	switch rType.Kind() {
+	case reflect.Bool:
+		return func(ptr unsafe.Pointer) bool { return *(*bool)(ptr) }
+	case reflect.String:
+		return func(ptr unsafe.Pointer) bool { return len(*(*string)(ptr)) > 0 }
+	case reflect.Slice:
+		return func(ptr unsafe.Pointer) bool { return len(*(*[]byte)(ptr)) > 0 } //slice header length does not depend on the element type
+	case reflect.Array:
+		isTrue := rType.Len() > 0
+		return func(ptr unsafe.Pointer) bool { return isTrue }
+	case reflect.Map, reflect.Chan:
+		return func(ptr unsafe.Pointer) bool { return reflect.NewAt(rType, ptr).Elem().Len() > 0 }
+	case reflect.Func, reflect.UnsafePointer:
+		return func(ptr unsafe.Pointer) bool { return *(*unsafe.Pointer)(ptr) != nil }
+	case reflect.Ptr:
+		isElemTrue := TruthFunc(rType.Elem(), zeroIsTrue)
+		return func(ptr unsafe.Pointer) bool {
+			elemPtr := *(*unsafe.Pointer)(ptr)
+			return elemPtr != nil && isElemTrue(elemPtr)
+		}
+	case reflect.Interface:
+		return func(ptr unsafe.Pointer) bool { return IsTrue(reflect.NewAt(rType, ptr).Elem(), zeroIsTrue) }
+	}
+
+	if isTrue, ok := numberTruthFunc(rType.Kind()); ok && !zeroIsTrue {
+		return isTrue
+	}
+	return func(ptr unsafe.Pointer) bool { return true }
+}
+
+//numberTruthFunc returns function checking if number is not zero, false if kind is not a number
+func numberTruthFunc(kind reflect.Kind) (func(ptr unsafe.Pointer) bool, bool) {
+	switch kind {
+	case reflect.Int8, reflect.Uint8:
+		return func(ptr unsafe.Pointer) bool { return *(*uint8)(ptr) != 0 }, true
+	case reflect.Int16, reflect.Uint16:
+		return func(ptr unsafe.Pointer) bool { return *(*uint16)(ptr) != 0 }, true
+	case reflect.Int32, reflect.Uint32:
+		return func(ptr unsafe.Pointer) bool { return *(*uint32)(ptr) != 0 }, true
+	case reflect.Int64, reflect.Uint64:
+		return func(ptr unsafe.Pointer) bool { return *(*uint64)(ptr) != 0 }, true
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return func(ptr unsafe.Pointer) bool { return *(*uint)(ptr) != 0 }, true
+	case reflect.Float32:
+		return func(ptr unsafe.Pointer) bool { return *(*float32)(ptr) != 0 }, true
+	case reflect.Float64:
+		return func(ptr unsafe.Pointer) bool { return *(*float64)(ptr) != 0 }, true
+	}
+	return nil, false
+}
+
+//IsTrue returns Velocity truthiness of the value
+func IsTrue(value reflect.Value, zeroIsTrue bool) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return value.Len() > 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return zeroIsTrue || value.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return zeroIsTrue || value.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return zeroIsTrue || value.Float() != 0
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return false
+		}
+		return IsTrue(value.Elem(), zeroIsTrue)
+	case reflect.Func, reflect.UnsafePointer:
+		return !value.IsNil()
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package expr
+
+import (
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/est"
+	"unsafe"
+)
+
+func computeBinaryUint(token ast.Token, binary *binaryExpr) (est.Compute, error) {
+	var compute func(x, y uint64) uint64
+	switch token {
+	case ast.ADD:
+		compute = func(x, y uint64) uint64 { return x + y }
+	case ast.SUB:
+		compute = func(x, y uint64) uint64 { return x - y }
+	case ast.MUL:
+		compute = func(x, y uint64) uint64 { return x * y }
+	case ast.QUO:
+		compute = func(x, y uint64) uint64 { return x / y }
+	case ast.MOD:
+		compute = func(x, y uint64) uint64 { return x % y }
+	default:
+		return computeCompare(token, binary, "uint64", func(x, y unsafe.Pointer) int {
+			return compareUint(*(*uint64)(x), *(*uint64)(y))
+		})
+	}
+
+	isDivision := token == ast.QUO || token == ast.MOD
+	return func(state *est.State) unsafe.Pointer {
+		x := binary.x.Exec(state)
+		y := binary.y.Exec(state)
+		z := binary.z.Pointer(state)
+		if isDivision && *(*uint64)(y) == 0 {
+			return binary.divisionByZero(state, z)
+		}
+		*(*uint64)(z) = compute(*(*uint64)(x), *(*uint64)(y))
+		return z
+	}, nil
+}
+
+//computeMixedSign compares int with uint64 operand, negative int is less than any uint64
+func computeMixedSign(token ast.Token, binary *binaryExpr, unsignedX bool) (est.Compute, error) {
+	compare := compareMixedSign
+	if unsignedX {
+		compare = func(x, y unsafe.Pointer) int { return -compareMixedSign(y, x) }
+	}
+	return computeCompare(token, binary, "int and uint64", compare)
+}
+
+func compareMixedSign(signed, unsigned unsafe.Pointer) int {
+	value := *(*int)(signed)
+	if value < 0 {
+		return -1
+	}
+	return compareUint(uint64(value), *(*uint64)(unsigned))
+}
+
+func compareUint(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+//computeCompare creates comparison of the operands ordered by compare, nil operand is less than any value
+func computeCompare(token ast.Token, binary *binaryExpr, dataType string, compare func(x, y unsafe.Pointer) int) (est.Compute, error) {
+	var matches func(order int) bool
+	switch token {
+	case ast.EQ:
+		matches = func(order int) bool { return order == 0 }
+	case ast.NEQ:
+		matches = func(order int) bool { return order != 0 }
+	case ast.LSS:
+		matches = func(order int) bool { return order < 0 }
+	case ast.LEQ:
+		matches = func(order int) bool { return order <= 0 }
+	case ast.GTR:
+		matches = func(order int) bool { return order > 0 }
+	case ast.GTE:
+		matches = func(order int) bool { return order >= 0 }
+	default:
+		return nil, errorUnsupported(token, dataType)
+	}
+
+	return func(state *est.State) unsafe.Pointer {
+		x := binary.x.Exec(state)
+		y := binary.y.Exec(state)
+		order := 0
+		switch {
+		case x == nil && y == nil:
+		case x == nil:
+			order = -1
+		case y == nil:
+			order = 1
+		default:
+			order = compare(x, y)
+		}
+
+		if matches(order) {
+			return est.TrueValuePtr
+		}
+		return est.FalseValuePtr
+	}, nil
+}
@@ -15,7 +15,8 @@ type Expression struct {
 	Type       reflect.Type
 	*Selector
 	est.New
-	Unify converter.UnifyFn
+	Unify    converter.UnifyFn
+	NullSafe bool //indirect selector yields nil if any selector in the chain is nil
 }
 
 func (e *Expression) Operand(control est.Control, options ...interface{}) (*Operand, error) {
@@ -89,6 +90,9 @@ func (e Expressions) Operands(control est.Control, shouldDerefLast bool) ([]*Ope
 
 func (e *Expression) newIndirectSelector(shouldDerefLast bool, refLast bool) est.Compute {
 	upstream := Upstream(e.Selector, shouldDerefLast, refLast)
+	if e.NullSafe {
+		upstream = NullSafeUpstream(e.Selector, shouldDerefLast, refLast)
+	}
 	return func(state *est.State) unsafe.Pointer {
 		ret := upstream(state)
 		return ret
@@ -50,6 +50,7 @@ type (
 		isVariadic  bool
 		iFaceMethod bool
 		caller      reflect.Value
+		funcType    reflect.Type
 	}
 
 	Function struct {
@@ -106,7 +107,7 @@ func (r *funcReceiver) aFunc(id string) (*Func, bool) {
 func (f *Func) CallFunc(accumulator *Selector, operands []*Operand, state *est.State) unsafe.Pointer {
 	anIface, err := f.Function(operands, state)
 	if err != nil {
-		state.AddError(err)
+		state.AddError(accumulator.Location.WithError(err))
 	}
 	if anIface != nil {
 		accumulator.SetValue(state.MemPtr, anIface)
@@ -168,28 +169,28 @@ func (f *Func) execute(operands []*Operand, state *est.State) ([]reflect.Value,
 		return caller.Call([]reflect.Value{}), nil
 	case 1:
 		return caller.Call([]reflect.Value{
-			f.ensureValue(operands[0].ExecInterface(state), operands[0].Type),
+			f.ensureArg(0, operands[0].ExecInterface(state), operands[0].Type),
 		}), nil
 
 	case 2:
 		return caller.Call([]reflect.Value{
-			f.ensureValue(operands[0].ExecInterface(state), operands[0].Type),
-			f.ensureValue(operands[1].ExecInterface(state), operands[1].Type),
+			f.ensureArg(0, operands[0].ExecInterface(state), operands[0].Type),
+			f.ensureArg(1, operands[1].ExecInterface(state), operands[1].Type),
 		}), nil
 
 	case 3:
 		return caller.Call([]reflect.Value{
-			f.ensureValue(operands[0].ExecInterface(state), operands[0].Type),
-			f.ensureValue(operands[1].ExecInterface(state), operands[1].Type),
-			f.ensureValue(operands[2].ExecInterface(state), operands[2].Type),
+			f.ensureArg(0, operands[0].ExecInterface(state), operands[0].Type),
+			f.ensureArg(1, operands[1].ExecInterface(state), operands[1].Type),
+			f.ensureArg(2, operands[2].ExecInterface(state), operands[2].Type),
 		}), nil
 
 	case 4:
 		return caller.Call([]reflect.Value{
-			f.ensureValue(operands[0].ExecInterface(state), operands[0].Type),
-			f.ensureValue(operands[1].ExecInterface(state), operands[1].Type),
-			f.ensureValue(operands[2].ExecInterface(state), operands[2].Type),
-			f.ensureValue(operands[3].ExecInterface(state), operands[3].Type),
+			f.ensureArg(0, operands[0].ExecInterface(state), operands[0].Type),
+			f.ensureArg(1, operands[1].ExecInterface(state), operands[1].Type),
+			f.ensureArg(2, operands[2].ExecInterface(state), operands[2].Type),
+			f.ensureArg(3, operands[3].ExecInterface(state), operands[3].Type),
 		}), nil
 
 	default:
@@ -200,7 +201,7 @@ func (f *Func) execute(operands []*Operand, state *est.State) ([]reflect.Value,
 			}
 
 			anInterface := operands[i].ExecInterface(state)
-			values = append(values, f.ensureValue(anInterface, operands[i].Type))
+			values = append(values, f.ensureArg(i, anInterface, operands[i].Type))
 		}
 
 		return caller.Call(values), nil
@@ -215,6 +216,36 @@ func (f *Func) ensureValue(anInterface interface{}, t reflect.Type) reflect.Valu
 	return reflect.ValueOf(anInterface)
 }
 
+//ensureArg dereferences pointer argument passed to the parameter of the pointer elem type, i.e. *time.Time to time.Time
+func (f *Func) ensureArg(i int, anInterface interface{}, t reflect.Type) reflect.Value {
+	value := f.ensureValue(anInterface, t)
+	paramType := f.paramType(i)
+	if paramType == nil || value.Kind() != reflect.Ptr || value.Type().Elem() != paramType {
+		return value
+	}
+
+	if value.IsNil() {
+		return reflect.Zero(paramType)
+	}
+	return value.Elem()
+}
+
+func (f *Func) paramType(i int) reflect.Type {
+	if f.funcType == nil {
+		return nil
+	}
+
+	numIn := f.funcType.NumIn()
+	if f.isVariadic && i >= numIn-1 {
+		return f.funcType.In(numIn - 1).Elem()
+	}
+
+	if i >= numIn {
+		return nil
+	}
+	return f.funcType.In(i)
+}
+
 func (f *Func) tryDiscoverReceiver(receiver interface{}, operands []*Operand, state *est.State, receiverValue reflect.Value) (func() (interface{}, error), bool) {
 	if operands[0].LiteralPtr != nil {
 		return nil, false
@@ -266,6 +297,7 @@ func NewFunctions(options ...interface{}) *Functions {
 	_ = result.RegisterFuncNs(functions.FuncTypes, functions.Types{})
 	_ = result.RegisterFuncNs(functions.FuncErrors, functions.Errors{})
 	_ = result.RegisterFuncNs(functions.FuncTime, functions.Time{})
+	_ = result.RegisterFuncNs(functions.FuncDate, functions.Date{})
 	_ = result.RegisterFuncNs(functions.FuncMaps, functions.Maps{})
 	_ = result.RegisterFuncNs(functions.FuncJSON, functions.NewJSON(typeLookup))
 	_ = result.RegisterFunctionKind(functions.MapHasKey, functions.HasKeyFunc)
@@ -344,6 +376,7 @@ func (f *Functions) newFunc(name string, funcType reflect.Type, resultType refle
 	aFunc := &Func{
 		Name:        name,
 		caller:      caller,
+		funcType:    funcType,
 		iFaceMethod: isNamedIFace,
 		ResultType:  resultType,
 		XType:       xunsafe.NewType(resultType),
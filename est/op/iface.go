@@ -15,12 +15,33 @@ type Interface struct {
 
 func (i *Interface) Exec(xPtr unsafe.Pointer, state *est.State) unsafe.Pointer {
 	asInterface := xunsafe.AsInterface(xPtr)
+	if asInterface == nil {
+		return nil
+	}
 
 	actualValue := reflect.ValueOf(asInterface)
 	switch actualValue.Type().Kind() {
 	case reflect.Map:
 		return i.aMap.Exec(xPtr, state)
+	case reflect.Slice, reflect.Array:
+		return i.elemAt(actualValue, state)
 	default:
 		return i.aSlice.Exec(xPtr, state)
 	}
 }
+
+//elemAt returns pointer to interface{} holding dynamic slice element, i.e. $cfg.db.tags[1] over decoded JSON
+func (i *Interface) elemAt(aSlice reflect.Value, state *est.State) unsafe.Pointer {
+	indexPtr := i.aSlice.IndexOperand.Exec(state)
+	if indexPtr == nil {
+		return nil
+	}
+
+	index := *(*int)(indexPtr)
+	if index < 0 || index >= aSlice.Len() {
+		return nil
+	}
+
+	elem := aSlice.Index(index).Interface()
+	return unsafe.Pointer(&elem)
+}
@@ -45,3 +45,13 @@ func (m *Map) Exec(mapPtr unsafe.Pointer, state *est.State) unsafe.Pointer {
 		return xunsafe.AsPointer(iface)
 	}
 }
+
+//MapOperand returns operand of the indexed map
+func (m *Map) MapOperand() *Operand {
+	return m.mapOperand
+}
+
+//KeyOperand returns operand of the map key
+func (m *Map) KeyOperand() *Operand {
+	return m.indexOperand
+}
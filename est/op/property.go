@@ -0,0 +1,121 @@
+package op
+
+import (
+	"github.com/viant/velty/est"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+var ifaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+type (
+	//Property represents dot-notation access on the dynamic interface value, i.e. $cfg.db.host over decoded JSON.
+	//Value type is resolved at runtime, matching getter is cached for the last seen type
+	Property struct {
+		name     string
+		xOperand *Operand
+		getter   atomic.Value
+	}
+
+	propertyGetter struct {
+		rType reflect.Type
+		get   func(value reflect.Value) (reflect.Value, bool)
+	}
+)
+
+//Exec returns pointer to interface{} holding the property value, or nil if value does not have the property
+func (p *Property) Exec(xPtr unsafe.Pointer, state *est.State) unsafe.Pointer {
+	iface := p.xOperand.AsInterface(xPtr)
+	if iface == nil {
+		return nil
+	}
+
+	if aMap, ok := iface.(map[string]interface{}); ok {
+		value, ok := aMap[p.name]
+		if !ok {
+			return nil
+		}
+		return unsafe.Pointer(&value)
+	}
+
+	value := reflect.ValueOf(iface)
+	result, ok := p.getterFor(value.Type())(value)
+	if !ok {
+		return nil
+	}
+
+	resultIface := result.Interface()
+	return unsafe.Pointer(&resultIface)
+}
+
+func (p *Property) getterFor(rType reflect.Type) func(value reflect.Value) (reflect.Value, bool) {
+	if cached, ok := p.getter.Load().(*propertyGetter); ok && cached.rType == rType {
+		return cached.get
+	}
+
+	getter := &propertyGetter{rType: rType, get: newPropertyGetter(rType, p.name)}
+	p.getter.Store(getter)
+	return getter.get
+}
+
+func newPropertyGetter(rType reflect.Type, name string) func(value reflect.Value) (reflect.Value, bool) {
+	switch rType.Kind() {
+	case reflect.Map:
+		if rType.Key().Kind() != reflect.String {
+			break
+		}
+
+		key := reflect.ValueOf(name).Convert(rType.Key())
+		return func(value reflect.Value) (reflect.Value, bool) {
+			result := value.MapIndex(key)
+			return result, result.IsValid()
+		}
+
+	case reflect.Struct:
+		if field, ok := propertyField(rType, name); ok {
+			return func(value reflect.Value) (reflect.Value, bool) {
+				return value.FieldByIndex(field.Index), true
+			}
+		}
+
+	case reflect.Ptr:
+		if rType.Elem().Kind() != reflect.Struct {
+			break
+		}
+
+		if field, ok := propertyField(rType.Elem(), name); ok {
+			return func(value reflect.Value) (reflect.Value, bool) {
+				if value.IsNil() {
+					return reflect.Value{}, false
+				}
+				return value.Elem().FieldByIndex(field.Index), true
+			}
+		}
+	}
+
+	return func(value reflect.Value) (reflect.Value, bool) {
+		return reflect.Value{}, false
+	}
+}
+
+func propertyField(rType reflect.Type, name string) (reflect.StructField, bool) {
+	for _, candidate := range []string{name, strings.ToUpper(name[:1]) + name[1:]} {
+		if field, ok := rType.FieldByName(candidate); ok && field.PkgPath == "" {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+//NewPropertySelector creates selector of the dynamic interface value property
+func NewPropertySelector(id string, name string, xOperand *Operand, parent *Selector) *Selector {
+	return &Selector{
+		Type:     ifaceType,
+		ID:       id,
+		Indirect: true,
+		Parent:   parent,
+		Property: &Property{name: name, xOperand: xOperand},
+	}
+}
@@ -1,6 +1,7 @@
 package op
 
 import (
+	"github.com/viant/velty/ast"
 	"github.com/viant/xunsafe"
 	types "github.com/viant/xunsafe/converter"
 	"reflect"
@@ -23,8 +24,10 @@ type Selector struct {
 	ParentOffset    uintptr
 	Map             *Map
 	InterfaceExec   *Interface
+	Property        *Property
 	Cycle           *Selector
 	IsFieldSelector bool
+	Location        *ast.Location
 }
 
 //NewSelector create a selector
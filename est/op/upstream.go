@@ -9,6 +9,15 @@ import (
 )
 
 func Upstream(selector *Selector, derefLast bool, refLast bool) func(state *est.State) unsafe.Pointer {
+	return upstream(selector, derefLast, refLast, false)
+}
+
+//NullSafeUpstream works like Upstream, but returns nil instead of the zero value if any selector in the chain is nil
+func NullSafeUpstream(selector *Selector, derefLast bool, refLast bool) func(state *est.State) unsafe.Pointer {
+	return upstream(selector, derefLast, refLast, true)
+}
+
+func upstream(selector *Selector, derefLast bool, refLast bool, nullSafe bool) func(state *est.State) unsafe.Pointer {
 	derefLast = derefLast || converter.IsPrimitive(selector.Type)
 	sel := selector.Parent
 	counter := -1
@@ -45,6 +54,10 @@ func Upstream(selector *Selector, derefLast bool, refLast bool) func(state *est.
 		zeroValuePtr = xunsafe.AsPointer(value)
 	}
 
+	if nullSafe {
+		zeroValuePtr = nil
+	}
+
 	shouldRefLast := selector.Type.Kind() == reflect.Ptr
 
 	return func(state *est.State) unsafe.Pointer {
@@ -83,6 +96,8 @@ func Upstream(selector *Selector, derefLast bool, refLast bool) func(state *est.
 				ptr = refIfNeeded(parents[i].Map.Exec(ptr, state), shouldRef)
 			} else if parents[i].InterfaceExec != nil {
 				ptr = refIfNeeded(parents[i].InterfaceExec.Exec(ptr, state), shouldRef)
+			} else if parents[i].Property != nil {
+				ptr = refIfNeeded(parents[i].Property.Exec(ptr, state), shouldRef)
 			} else {
 				if ((!derefLast || shouldRef) && i == parentLen-1) || (i < parentLen-1 && parents[i+1].Func != nil) {
 					ptr = parents[i].Pointer(ptr)
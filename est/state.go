@@ -17,6 +17,7 @@ type State struct {
 	Buffer       *Buffer
 	Errors       []error
 	PanicOnError bool
+	Control      Control //pending #break, #continue or #stop request
 	isTaken      bool
 }
 
@@ -27,8 +28,10 @@ func (s *State) SetValue(k string, v interface{}) error {
 	}
 
 	switch xField.Kind() {
-	case reflect.Ptr, reflect.Struct, reflect.Slice, reflect.Map, reflect.Interface:
+	case reflect.Ptr, reflect.Struct, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func:
 		xField.SetValue(s.MemPtr, v)
+	case reflect.Chan:
+		reflect.NewAt(xField.Type, xField.Pointer(s.MemPtr)).Elem().Set(reflect.ValueOf(v))
 	default:
 		xField.Set(s.MemPtr, v)
 	}
@@ -73,6 +76,7 @@ func (s *State) Reset() {
 
 	s.Buffer.Reset()
 	s.Errors = nil
+	s.Control = 0
 	s.isTaken = true
 }
 
@@ -0,0 +1,70 @@
+package assign
+
+import (
+	"github.com/viant/velty/est"
+	op2 "github.com/viant/velty/est/op"
+	"github.com/viant/velty/keys"
+	"reflect"
+	"unsafe"
+)
+
+type entry struct {
+	aMap    *op2.Map
+	mapType reflect.Type
+	y       *op2.Operand
+}
+
+func (e *entry) assign(state *est.State) unsafe.Pointer {
+	mapPtr := e.aMap.MapOperand().Exec(state)
+	srcPtr := e.y.Exec(state)
+	if mapPtr == nil || srcPtr == nil {
+		return srcPtr
+	}
+
+	key, ok := e.key(state)
+	if !ok {
+		return srcPtr
+	}
+
+	aMap := reflect.NewAt(e.mapType, mapPtr).Elem()
+	if aMap.IsNil() {
+		aMap.Set(reflect.MakeMap(e.mapType))
+	}
+
+	aMap.SetMapIndex(key, e.value(srcPtr))
+	return srcPtr
+}
+
+func (e *entry) key(state *est.State) (reflect.Value, bool) {
+	key := reflect.ValueOf(keys.Normalize(e.aMap.KeyOperand().ExecInterface(state)))
+	keyType := e.mapType.Key()
+	switch {
+	case !key.IsValid():
+		return key, false
+	case key.Type().AssignableTo(keyType):
+		return key, true
+	case key.Type().ConvertibleTo(keyType):
+		return key.Convert(keyType), true
+	}
+	return key, false
+}
+
+func (e *entry) value(srcPtr unsafe.Pointer) reflect.Value {
+	value := reflect.NewAt(e.y.Type, srcPtr).Elem()
+	if elemType := e.mapType.Elem(); !value.Type().AssignableTo(elemType) {
+		return value.Convert(elemType)
+	}
+	return value
+}
+
+//Entry creates assignment of the map entry i.e. #set($m["key"] = 10), the map is created if it was nil
+func Entry(aMap *op2.Map, mapType reflect.Type, yExpr *op2.Expression) (est.New, error) {
+	return func(control est.Control) (est.Compute, error) {
+		y, err := yExpr.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+
+		return (&entry{aMap: aMap, mapType: mapType, y: y}).assign, nil
+	}, nil
+}
@@ -17,6 +17,17 @@ func (s *Block) compute(state *est.State) unsafe.Pointer {
 	return result
 }
 
+//computeInterruptible stops executing statements once #break, #continue or #stop was requested
+func (s *Block) computeInterruptible(state *est.State) unsafe.Pointer {
+	var result unsafe.Pointer
+	for i := 0; i < len(s.Stmt); i++ {
+		if result = s.Stmt[i](state); state.Control != 0 {
+			return result
+		}
+	}
+	return result
+}
+
 type stmt1 struct {
 	est.Compute
 }
@@ -609,6 +620,12 @@ func NewBlock(stmtsNew []est.New) est.New {
 		if err != nil {
 			return nil, err
 		}
+
+		if control != 0 && len(stmts) > 1 {
+			b := &Block{Stmt: stmts}
+			return b.computeInterruptible, nil
+		}
+
 		switch len(stmts) {
 		case 0:
 			return nop, nil
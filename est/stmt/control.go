@@ -0,0 +1,35 @@
+package stmt
+
+import (
+	"github.com/viant/velty/est"
+	"unsafe"
+)
+
+//Interrupt creates a compute requesting #break, #continue or #stop
+func Interrupt(request est.Control) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		return func(state *est.State) unsafe.Pointer {
+			state.Control = request
+			return nil
+		}, nil
+	}
+}
+
+//interrupted consumes loop scoped request, returns true if the loop has to be terminated
+func interrupted(state *est.State) bool {
+	switch state.Control {
+	case est.HasContinue:
+		state.Control = 0
+		return false
+	case est.HasBreak:
+		state.Control = 0
+	}
+	return true
+}
+
+//consumeBreak consumes #break request used to exit the macro
+func consumeBreak(state *est.State) {
+	if state.Control == est.HasBreak {
+		state.Control = 0
+	}
+}
@@ -25,7 +25,20 @@ func (f *For) compute(state *est.State) unsafe.Pointer {
 	return ptr
 }
 
-func ForLoop(init, post est.New, condition *op.Expression, block est.Compute) (est.New, error) {
+func (f *For) computeInterruptible(state *est.State) unsafe.Pointer {
+	var ptr unsafe.Pointer
+	for f.Init(state); *(*bool)(f.Condition.Exec(state)); f.Post(state) {
+		ptr = f.Block(state)
+		if state.Control != 0 && interrupted(state) {
+			break
+		}
+	}
+
+	return ptr
+}
+
+//ForLoop creates #for loop, bodyControl represents #break, #continue and #stop usage within the loop body
+func ForLoop(init, post est.New, condition *op.Expression, block est.Compute, bodyControl est.Control) (est.New, error) {
 	return func(control est.Control) (est.Compute, error) {
 		forLoop := &For{}
 		var err error
@@ -46,7 +59,9 @@ func ForLoop(init, post est.New, condition *op.Expression, block est.Compute) (e
 		}
 
 		forLoop.Block = block
-
+		if bodyControl != 0 {
+			return forLoop.computeInterruptible, nil
+		}
 		return forLoop.compute, nil
 	}, nil
 }
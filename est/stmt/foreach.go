@@ -15,6 +15,9 @@ type ForEach struct {
 	X    *op.Operand
 
 	*xunsafe.Slice
+	Meta   *LoopMeta
+	Else   est.Compute
+	assign func(state *est.State, xPtr unsafe.Pointer, i int)
 }
 
 func (e *ForEach) compute(state *est.State) unsafe.Pointer {
@@ -86,19 +89,80 @@ func (e *ForEach) computeLiteral(state *est.State) unsafe.Pointer {
 	return resultPtr
 }
 
-func ForEachLoop(block est.New, itemExpr *op.Expression, sliceExpr *op.Expression) (est.New, error) {
+//computeInterface copies interface{} items as is, including nil ones
+func (e *ForEach) computeInterface(state *est.State) unsafe.Pointer {
+	xPtr := e.X.Exec(state)
+	l := e.Slice.Len(xPtr)
+	itemPtr := e.Item.Sel.Pointer(state.MemPtr)
+
+	var resultPtr unsafe.Pointer
+	for i := 0; i < l; i++ {
+		*(*interface{})(itemPtr) = *(*interface{})(e.Slice.PointerAt(xPtr, uintptr(i)))
+		resultPtr = e.Block(state)
+	}
+
+	return resultPtr
+}
+
+//computeControlled maintains loop metadata, runs #else branch and handles #break, #continue and #stop requests
+func (e *ForEach) computeControlled(state *est.State) unsafe.Pointer {
+	xPtr := e.X.Exec(state)
+	l := 0
+	if xPtr != nil {
+		l = e.Slice.Len(xPtr)
+	}
+
+	if l == 0 && e.Else != nil {
+		return e.Else(state)
+	}
+
+	iter := e.Meta.begin(state)
+
+	var resultPtr unsafe.Pointer
+	for i := 0; i < l; i++ {
+		e.assign(state, xPtr, i)
+		iter.at(i, l)
+		resultPtr = e.Block(state)
+		if state.Control != 0 && interrupted(state) {
+			break
+		}
+	}
+
+	return resultPtr
+}
+
+func (e *ForEach) assignValue(state *est.State, xPtr unsafe.Pointer, i int) {
+	e.Item.Sel.SetValue(state.MemPtr, e.Slice.ValueAt(xPtr, i))
+}
+
+func (e *ForEach) assignPtr(state *est.State, xPtr unsafe.Pointer, i int) {
+	e.Item.Sel.SetValue(state.MemPtr, e.Slice.ValuePointerAt(xPtr, i))
+}
+
+func (e *ForEach) assignInterface(state *est.State, xPtr unsafe.Pointer, i int) {
+	*(*interface{})(e.Item.Sel.Pointer(state.MemPtr)) = *(*interface{})(e.Slice.PointerAt(xPtr, uintptr(i)))
+}
+
+//ForEachLoop creates #foreach loop over slice, bodyControl represents #break, #continue and #stop usage within the loop body
+func ForEachLoop(block est.New, itemExpr *op.Expression, sliceExpr *op.Expression, meta *LoopMeta, elseBlock est.New, bodyControl est.Control) (est.New, error) {
 	return func(control est.Control) (est.Compute, error) {
 		aSlice, err := sliceExpr.Operand(control)
 		if err != nil {
 			return nil, err
 		}
 
-		loop := &ForEach{}
-		loop.Block, err = block(control)
+		loop := &ForEach{Meta: meta}
+		loop.Block, err = block(bodyControl)
 		if err != nil {
 			return nil, err
 		}
 
+		if elseBlock != nil {
+			if loop.Else, err = elseBlock(control); err != nil {
+				return nil, err
+			}
+		}
+
 		loop.Slice = xunsafe.NewSlice(aSlice.Type)
 		loop.X = aSlice
 
@@ -107,14 +171,38 @@ func ForEachLoop(block est.New, itemExpr *op.Expression, sliceExpr *op.Expressio
 			return nil, err
 		}
 
-		switch loop.Slice.Elem().Kind() {
+		elemType := loop.Slice.Elem()
+		if bodyControl != 0 || meta != nil || elseBlock != nil {
+			switch {
+			case elemType.Kind() == reflect.Interface && elemType.NumMethod() == 0 && loop.Item.Type == elemType:
+				loop.assign = loop.assignInterface
+			case elemType.Kind() == reflect.Ptr:
+				loop.assign = loop.assignPtr
+			default:
+				loop.assign = loop.assignValue
+			}
+			return loop.computeControlled, nil
+		}
+
+		switch elemType.Kind() {
+		case reflect.Interface:
+			if elemType.NumMethod() == 0 && loop.Item.Type == elemType {
+				return loop.computeInterface, nil
+			}
+			if loop.X.IsIndirect() {
+				return loop.computeIndirect, nil
+			}
+			if loop.X.Sel != nil {
+				return loop.compute, nil
+			}
+			return loop.computeLiteral, nil
 		case reflect.Ptr:
-			if loop.X.Sel != nil && loop.X.Sel.Indirect {
+			if loop.X.IsIndirect() {
 				return loop.computeIndirectPtr, nil
 			}
 			return loop.computePtr, nil
 		default:
-			if loop.X.Sel != nil && loop.X.Sel.Indirect {
+			if loop.X.IsIndirect() {
 				return loop.computeIndirect, nil
 			}
 
@@ -0,0 +1,264 @@
+package stmt
+
+import (
+	"fmt"
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+//Iterate represents #foreach over map, iterator function (iter.Seq, iter.Seq2) or channel
+type Iterate struct {
+	Block  est.Compute
+	Item   *op.Operand
+	X      *op.Operand
+	Yield  *op.Selector
+	Meta   *LoopMeta
+	Else   est.Compute
+	Sorted bool
+	xType  reflect.Type
+	item   reflect.Type
+}
+
+//Yield holds iterator function callback, created once per state and reused by the subsequent loop executions
+type Yield struct {
+	callback reflect.Value
+	args     []reflect.Value
+	consume  func(values ...reflect.Value) bool
+}
+
+//YieldType represents Yield type, state field of that type is required for #foreach over iterator function
+var YieldType = reflect.TypeOf(Yield{})
+
+//entryKey and entryValue are field indexes of the type created with EntryType
+const (
+	entryKey   = 0
+	entryValue = 1
+)
+
+//EntryType returns map or iter.Seq2 entry type, exposed in the template as $e.Key and $e.Value
+func EntryType(keyType, valueType reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Key", Type: keyType},
+		{Name: "Value", Type: valueType},
+	})
+}
+
+//IterationItemType returns #foreach item type for map, iterator function or channel, false if type is not supported
+func IterationItemType(rType reflect.Type) (reflect.Type, bool) {
+	switch rType.Kind() {
+	case reflect.Map:
+		return EntryType(rType.Key(), rType.Elem()), true
+	case reflect.Chan:
+		if rType.ChanDir()&reflect.RecvDir == 0 {
+			return nil, false
+		}
+		return rType.Elem(), true
+	case reflect.Func:
+		return seqItemType(rType)
+	}
+	return nil, false
+}
+
+func (it *Iterate) value(state *est.State) (reflect.Value, bool) {
+	xPtr := it.X.Exec(state)
+	if xPtr == nil {
+		return reflect.Value{}, false
+	}
+
+	value := reflect.NewAt(it.xType, xPtr).Elem()
+	return value, !value.IsNil()
+}
+
+//run executes loop body for the current item, returns false if loop was interrupted
+func (it *Iterate) run(state *est.State, iter iteration, index int, last bool) (unsafe.Pointer, bool) {
+	iter.set(index, last)
+	result := it.Block(state)
+	if state.Control != 0 && interrupted(state) {
+		return result, false
+	}
+	return result, true
+}
+
+func (it *Iterate) itemValue(state *est.State) reflect.Value {
+	return reflect.NewAt(it.item, it.Item.Sel.Pointer(state.MemPtr)).Elem()
+}
+
+func (it *Iterate) computeMap(state *est.State) unsafe.Pointer {
+	aMap, ok := it.value(state)
+	size := 0
+	if ok {
+		size = aMap.Len()
+	}
+
+	if size == 0 {
+		return it.empty(state)
+	}
+
+	var keys []reflect.Value
+	if it.Sorted {
+		keys = aMap.MapKeys()
+		sortKeys(keys)
+	}
+
+	iter := it.Meta.begin(state)
+	item := it.itemValue(state)
+	var result unsafe.Pointer
+	if keys != nil {
+		index := 0
+		for i, key := range keys {
+			value := aMap.MapIndex(key)
+			if !value.IsValid() { //entry was deleted by the loop body
+				continue
+			}
+
+			item.Field(entryKey).Set(key)
+			item.Field(entryValue).Set(value)
+			if result, ok = it.run(state, iter, index, i == size-1); !ok {
+				break
+			}
+			index++
+		}
+		return result
+	}
+
+	mapIter := aMap.MapRange()
+	for i := 0; mapIter.Next(); i++ {
+		item.Field(entryKey).Set(mapIter.Key())
+		item.Field(entryValue).Set(mapIter.Value())
+		if result, ok = it.run(state, iter, i, i == size-1); !ok {
+			break
+		}
+	}
+	return result
+}
+
+func (it *Iterate) computeChan(state *est.State) unsafe.Pointer {
+	aChan, ok := it.value(state)
+	if !ok {
+		return it.empty(state)
+	}
+
+	return it.iterate(state, func(yield func(values ...reflect.Value) bool) {
+		for {
+			value, ok := aChan.Recv()
+			if !ok || !yield(value) {
+				return
+			}
+		}
+	})
+}
+
+//iterate runs loop body for each produced item, with loop metadata it looks one item ahead to detect the last one
+func (it *Iterate) iterate(state *est.State, produce func(yield func(values ...reflect.Value) bool)) unsafe.Pointer {
+	iter := it.Meta.begin(state)
+	item := it.itemValue(state)
+	var result unsafe.Pointer
+	count := 0
+	if it.Meta == nil {
+		produce(func(values ...reflect.Value) bool {
+			it.setItem(item, values)
+			var ok bool
+			result, ok = it.run(state, iter, count, false)
+			count++
+			return ok
+		})
+	} else {
+		var pending []reflect.Value
+		produce(func(values ...reflect.Value) bool {
+			if pending != nil {
+				it.setItem(item, pending)
+				var ok bool
+				if result, ok = it.run(state, iter, count-1, false); !ok {
+					pending = nil
+					return false
+				}
+			}
+			pending = append(pending[:0], values...)
+			count++
+			return true
+		})
+
+		if pending != nil {
+			it.setItem(item, pending)
+			result, _ = it.run(state, iter, count-1, true)
+		}
+	}
+
+	if count == 0 {
+		return it.empty(state)
+	}
+	return result
+}
+
+func (it *Iterate) setItem(item reflect.Value, values []reflect.Value) {
+	if len(values) == 1 {
+		item.Set(values[0])
+		return
+	}
+
+	item.Field(entryKey).Set(values[0])
+	item.Field(entryValue).Set(values[1])
+}
+
+func (it *Iterate) empty(state *est.State) unsafe.Pointer {
+	if it.Else != nil {
+		return it.Else(state)
+	}
+	return est.EmptyStringPtr
+}
+
+//sortKeys sorts map keys, numbers and strings are sorted naturally, other keys by their string representation
+func sortKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		x, y := keys[i], keys[j]
+		switch x.Kind() {
+		case reflect.String:
+			return x.String() < y.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return x.Int() < y.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return x.Uint() < y.Uint()
+		case reflect.Float32, reflect.Float64:
+			return x.Float() < y.Float()
+		}
+		return fmt.Sprint(x.Interface()) < fmt.Sprint(y.Interface())
+	})
+}
+
+//IterateLoop creates #foreach loop over map, iterator function or channel, bodyControl represents #break, #continue and #stop usage within the loop body,
+//yield selects Yield state field used by the iterator function loop
+func IterateLoop(block est.New, itemExpr, xExpr *op.Expression, yield *op.Selector, meta *LoopMeta, elseBlock est.New, sorted bool, bodyControl est.Control) (est.New, error) {
+	return func(control est.Control) (est.Compute, error) {
+		loop := &Iterate{Yield: yield, Meta: meta, Sorted: sorted, xType: xExpr.Type, item: itemExpr.Type}
+		var err error
+		if loop.Block, err = block(bodyControl); err != nil {
+			return nil, err
+		}
+
+		if elseBlock != nil {
+			if loop.Else, err = elseBlock(control); err != nil {
+				return nil, err
+			}
+		}
+
+		if loop.X, err = xExpr.Operand(control); err != nil {
+			return nil, err
+		}
+
+		if loop.Item, err = itemExpr.Operand(control); err != nil {
+			return nil, err
+		}
+
+		switch xExpr.Type.Kind() {
+		case reflect.Map:
+			return loop.computeMap, nil
+		case reflect.Chan:
+			return loop.computeChan, nil
+		default:
+			return loop.computeSeq, nil
+		}
+	}, nil
+}
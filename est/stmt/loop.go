@@ -0,0 +1,59 @@
+package stmt
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+)
+
+//Loop represents $foreach loop metadata
+type Loop struct {
+	Index   int   `velty:"name=index"`
+	Count   int   `velty:"name=count"`
+	HasNext bool  `velty:"name=hasNext"`
+	First   bool  `velty:"name=first"`
+	Last    bool  `velty:"name=last"`
+	Parent  *Loop `velty:"name=parent"`
+}
+
+//LoopMeta maintains $foreach and $velocityCount variables of the loop
+type LoopMeta struct {
+	Loop          *op.Selector
+	Parent        *op.Selector
+	VelocityCount *op.Selector
+}
+
+//iteration holds loop metadata pointers for a single loop execution
+type iteration struct {
+	loop          *Loop
+	velocityCount *int
+}
+
+func (m *LoopMeta) begin(state *est.State) iteration {
+	if m == nil {
+		return iteration{}
+	}
+
+	loop := (*Loop)(m.Loop.Pointer(state.MemPtr))
+	*loop = Loop{}
+	if m.Parent != nil {
+		loop.Parent = (*Loop)(m.Parent.Pointer(state.MemPtr))
+	}
+	return iteration{loop: loop, velocityCount: (*int)(m.VelocityCount.Pointer(state.MemPtr))}
+}
+
+func (i iteration) at(index, size int) {
+	i.set(index, index == size-1)
+}
+
+func (i iteration) set(index int, last bool) {
+	if i.loop == nil {
+		return
+	}
+
+	i.loop.Index = index
+	i.loop.Count = index + 1
+	i.loop.First = index == 0
+	i.loop.HasNext = !last
+	i.loop.Last = last
+	*i.velocityCount = index + 1
+}
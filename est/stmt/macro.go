@@ -0,0 +1,92 @@
+package stmt
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"github.com/viant/xunsafe"
+	"unsafe"
+)
+
+type MacroCall struct {
+	Args    est.Compute
+	Body    est.Compute
+	Content est.Compute
+	Slot    *op.Selector
+	Params  []*op.Selector
+	Saved   []*op.Selector
+}
+
+func (m *MacroCall) compute(state *est.State) unsafe.Pointer {
+	m.save(state)
+	m.Args(state)
+	result := m.Body(state)
+	m.restore(state)
+	consumeBreak(state)
+	return result
+}
+
+func (m *MacroCall) computeWithContent(state *est.State) unsafe.Pointer {
+	m.save(state)
+	m.Args(state)
+	slot := (*est.Compute)(m.Slot.Field.Pointer(state.MemPtr))
+	prev := *slot
+	*slot = m.Content
+	result := m.Body(state)
+	*slot = prev
+	m.restore(state)
+	consumeBreak(state)
+	return result
+}
+
+func (m *MacroCall) save(state *est.State) {
+	for i, param := range m.Params {
+		xunsafe.Copy(m.Saved[i].Field.Pointer(state.MemPtr), param.Field.Pointer(state.MemPtr), int(param.Type.Size()))
+	}
+}
+
+func (m *MacroCall) restore(state *est.State) {
+	for i, param := range m.Params {
+		xunsafe.Copy(param.Field.Pointer(state.MemPtr), m.Saved[i].Field.Pointer(state.MemPtr), int(param.Type.Size()))
+	}
+}
+
+//NewMacroCall creates macro invocation, params values are copied to saved before and restored after the call,
+//so that the macro can be invoked from its own block content. Content and slot are used only by block macros,
+//where slot holds the call site content rendered by the $bodyContent
+func NewMacroCall(args []est.New, body est.New, params, saved []*op.Selector, content est.New, slot *op.Selector) est.New {
+	argsNew := NewBlock(args)
+	return func(control est.Control) (est.Compute, error) {
+		call := &MacroCall{Slot: slot, Params: params, Saved: saved}
+		var err error
+		if call.Args, err = argsNew(control); err != nil {
+			return nil, err
+		}
+
+		if call.Body, err = body(control); err != nil {
+			return nil, err
+		}
+
+		if content == nil {
+			return call.compute, nil
+		}
+
+		if call.Content, err = content(control); err != nil {
+			return nil, err
+		}
+
+		return call.computeWithContent, nil
+	}
+}
+
+//BodyContent renders block macro content stored in the slot
+func BodyContent(slot *op.Selector) est.New {
+	return func(control est.Control) (est.Compute, error) {
+		return func(state *est.State) unsafe.Pointer {
+			content := *(*est.Compute)(slot.Field.Pointer(state.MemPtr))
+			if content == nil {
+				return nil
+			}
+			return content(state)
+		}, nil
+	}
+}
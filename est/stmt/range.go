@@ -0,0 +1,107 @@
+package stmt
+
+import (
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"unsafe"
+)
+
+//Range iterates over integer range without materializing a slice
+type Range struct {
+	Block     est.Compute
+	Item      *op.Operand
+	From      *op.Operand
+	To        *op.Operand
+	Inclusive bool
+	Meta      *LoopMeta
+	Else      est.Compute
+}
+
+func (r *Range) compute(state *est.State) unsafe.Pointer {
+	from := *(*int)(r.From.Exec(state))
+	to := *(*int)(r.To.Exec(state))
+	step := RangeStep(from, to)
+	if r.Inclusive {
+		to += step
+	}
+
+	itemPtr := r.Item.Pointer(state)
+	var resultPtr unsafe.Pointer
+	for i := from; i != to; i += step {
+		*(*int)(itemPtr) = i
+		resultPtr = r.Block(state)
+	}
+
+	return resultPtr
+}
+
+//computeControlled maintains loop metadata, runs #else branch and handles #break, #continue and #stop requests
+func (r *Range) computeControlled(state *est.State) unsafe.Pointer {
+	from := *(*int)(r.From.Exec(state))
+	to := *(*int)(r.To.Exec(state))
+	step := RangeStep(from, to)
+	if r.Inclusive {
+		to += step
+	}
+
+	size := (to - from) * step
+	if size == 0 && r.Else != nil {
+		return r.Else(state)
+	}
+
+	iter := r.Meta.begin(state)
+	itemPtr := r.Item.Pointer(state)
+	var resultPtr unsafe.Pointer
+	for i := from; i != to; i += step {
+		*(*int)(itemPtr) = i
+		iter.at((i-from)*step, size)
+		resultPtr = r.Block(state)
+		if state.Control != 0 && interrupted(state) {
+			break
+		}
+	}
+
+	return resultPtr
+}
+
+//RangeStep returns 1 for ascending and -1 for descending range
+func RangeStep(from, to int) int {
+	if from > to {
+		return -1
+	}
+	return 1
+}
+
+//RangeLoop creates #foreach loop over integer range, bodyControl represents #break, #continue and #stop usage within the loop body
+func RangeLoop(block est.New, itemExpr, fromExpr, toExpr *op.Expression, inclusive bool, meta *LoopMeta, elseBlock est.New, bodyControl est.Control) (est.New, error) {
+	return func(control est.Control) (est.Compute, error) {
+		loop := &Range{Inclusive: inclusive, Meta: meta}
+		var err error
+		if loop.Block, err = block(bodyControl); err != nil {
+			return nil, err
+		}
+
+		if loop.Item, err = itemExpr.Operand(control); err != nil {
+			return nil, err
+		}
+
+		if loop.From, err = fromExpr.Operand(control); err != nil {
+			return nil, err
+		}
+
+		if loop.To, err = toExpr.Operand(control); err != nil {
+			return nil, err
+		}
+
+		if elseBlock != nil {
+			if loop.Else, err = elseBlock(control); err != nil {
+				return nil, err
+			}
+		}
+
+		if bodyControl != 0 || meta != nil || elseBlock != nil {
+			return loop.computeControlled, nil
+		}
+		return loop.compute, nil
+	}, nil
+}
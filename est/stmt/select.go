@@ -11,6 +11,8 @@ import (
 	"unsafe"
 )
 
+var timeType = reflect.TypeOf(time.Time{})
+
 type directAppender struct {
 	x *op.Operand
 }
@@ -41,7 +43,10 @@ func (a *directAppender) appendFloat64(state *est.State) unsafe.Pointer {
 }
 
 func (a *directAppender) newAppendStringIndirect() est.Compute {
-	upstream := op.Upstream(a.x.Sel, true, false)
+	upstream := a.x.Exec
+	if a.x.Sel != nil {
+		upstream = op.Upstream(a.x.Sel, true, false)
+	}
 
 	return func(state *est.State) unsafe.Pointer {
 		ret := upstream(state)
@@ -74,6 +79,59 @@ func (a *directAppender) newAppendFloatIndirect() est.Compute {
 	}
 }
 
+//newNumericAppender renders int8..int64, uint and float32 values
+func (a *directAppender) newNumericAppender(kind reflect.Kind) est.Compute {
+	var appendValue func(buffer *est.Buffer, ptr unsafe.Pointer)
+	switch kind {
+	case reflect.Int8:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendInt64(int64(*(*int8)(ptr))) }
+	case reflect.Int16:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendInt64(int64(*(*int16)(ptr))) }
+	case reflect.Int32:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendInt64(int64(*(*int32)(ptr))) }
+	case reflect.Int64:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendInt64(*(*int64)(ptr)) }
+	case reflect.Uint:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendUint64(uint64(*(*uint)(ptr))) }
+	case reflect.Uint8:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendUint64(uint64(*(*uint8)(ptr))) }
+	case reflect.Uint16:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendUint64(uint64(*(*uint16)(ptr))) }
+	case reflect.Uint32:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendUint64(uint64(*(*uint32)(ptr))) }
+	case reflect.Uint64:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendUint64(*(*uint64)(ptr)) }
+	case reflect.Float32:
+		appendValue = func(buffer *est.Buffer, ptr unsafe.Pointer) { buffer.AppendFloat32(*(*float32)(ptr)) }
+	default:
+		return nil
+	}
+
+	return func(state *est.State) unsafe.Pointer {
+		ptr := a.x.Exec(state)
+		appendValue(state.Buffer, ptr)
+		return ptr
+	}
+}
+
+//newTimeAppender renders time with the buffer time layout, without the layout time is rendered as JSON value, like other structs
+func (a *directAppender) newTimeAppender(isPtr bool) est.Compute {
+	generic := a.newGenericAppender()
+	return func(state *est.State) unsafe.Pointer {
+		if state.Buffer.TimeLayout() == "" {
+			return generic(state)
+		}
+
+		ptr := a.x.Exec(state)
+		if !isPtr {
+			state.Buffer.AppendTime(*(*time.Time)(ptr))
+		} else if timePtr := *(**time.Time)(ptr); timePtr != nil {
+			state.Buffer.AppendTime(*timePtr)
+		}
+		return ptr
+	}
+}
+
 func (a *directAppender) appendSelectorName() est.Compute {
 	asPtr := unsafe.Pointer(&a.x.Sel.Placeholder)
 	return func(state *est.State) unsafe.Pointer {
@@ -91,12 +149,20 @@ func (a *directAppender) newInterfaceAppender() est.Compute {
 			state.Buffer.AppendString(actual)
 		case int:
 			state.Buffer.AppendInt(actual)
+		case int64:
+			state.Buffer.AppendInt64(actual)
+		case uint64:
+			state.Buffer.AppendUint64(actual)
+		case float32:
+			state.Buffer.AppendFloat32(actual)
 		case float64:
 			state.Buffer.AppendFloat(actual)
 		case time.Time:
-			state.Buffer.AppendString(actual.Format(time.RFC3339))
+			state.Buffer.AppendTime(actual)
 		case *time.Time:
-			state.Buffer.AppendString(actual.Format(time.RFC3339))
+			if actual != nil {
+				state.Buffer.AppendTime(*actual)
+			}
 		case bool:
 			state.Buffer.AppendBool(actual)
 		default:
@@ -133,32 +199,45 @@ func Selector(expr *op.Expression, derefLast bool) est.New {
 
 		switch expr.Type.Kind() {
 		case reflect.Int:
-			if !x.Sel.Indirect {
+			if !x.IsIndirect() {
 				return result.appendInt, nil
 			}
 			return result.newAppendIntIndirect(), nil
 
 		case reflect.String:
-			if !x.Sel.Indirect {
+			if !x.IsIndirect() {
 				return result.appendString, nil
 			}
 			return result.newAppendStringIndirect(), nil
 
 		case reflect.Bool:
-			if !x.Sel.Indirect {
+			if !x.IsIndirect() {
 				return result.appendBool, nil
 			}
 			return result.newAppendBoolIndirect(), nil
 
 		case reflect.Float64:
-			if !x.Sel.Indirect {
+			if !x.IsIndirect() {
 				return result.appendFloat64, nil
 			}
 			return result.newAppendFloatIndirect(), nil
 
 		case reflect.Interface:
 			return result.newInterfaceAppender(), nil
+		case reflect.Struct:
+			if expr.Type == timeType {
+				return result.newTimeAppender(false), nil
+			}
+			return result.newGenericAppender(), nil
+		case reflect.Ptr:
+			if expr.Type.Elem() == timeType {
+				return result.newTimeAppender(true), nil
+			}
+			return result.newGenericAppender(), nil
 		default:
+			if appender := result.newNumericAppender(expr.Type.Kind()); appender != nil {
+				return appender, nil
+			}
 			return result.newGenericAppender(), nil
 		}
 	}
@@ -0,0 +1,57 @@
+//go:build go1.23
+
+package stmt
+
+import (
+	"github.com/viant/velty/est"
+	"reflect"
+	"unsafe"
+)
+
+var (
+	yieldContinue = []reflect.Value{reflect.ValueOf(true)}
+	yieldBreak    = []reflect.Value{reflect.ValueOf(false)}
+)
+
+//seqItemType returns #foreach item type for iter.Seq or iter.Seq2 alike function, i.e. func(yield func(V) bool)
+func seqItemType(rType reflect.Type) (reflect.Type, bool) {
+	if rType.NumIn() != 1 || rType.NumOut() != 0 || rType.IsVariadic() {
+		return nil, false
+	}
+
+	yield := rType.In(0)
+	if yield.Kind() != reflect.Func || yield.NumOut() != 1 || yield.Out(0).Kind() != reflect.Bool {
+		return nil, false
+	}
+
+	switch yield.NumIn() {
+	case 1:
+		return yield.In(0), true
+	case 2:
+		return EntryType(yield.In(0), yield.In(1)), true
+	}
+	return nil, false
+}
+
+func (it *Iterate) computeSeq(state *est.State) unsafe.Pointer {
+	aSeq, ok := it.value(state)
+	if !ok {
+		return it.empty(state)
+	}
+
+	holder := (*Yield)(it.Yield.Pointer(state.MemPtr))
+	if !holder.callback.IsValid() {
+		holder.callback = reflect.MakeFunc(it.xType.In(0), func(args []reflect.Value) []reflect.Value {
+			if holder.consume(args...) {
+				return yieldContinue
+			}
+			return yieldBreak
+		})
+		holder.args = []reflect.Value{holder.callback}
+	}
+
+	return it.iterate(state, func(yield func(values ...reflect.Value) bool) {
+		holder.consume = yield
+		aSeq.Call(holder.args)
+	})
+}
@@ -0,0 +1,18 @@
+//go:build !go1.23
+
+package stmt
+
+import (
+	"github.com/viant/velty/est"
+	"reflect"
+	"unsafe"
+)
+
+//seqItemType reports iterator functions as unsupported, iter.Seq and iter.Seq2 are supported with go1.23 or later
+func seqItemType(_ reflect.Type) (reflect.Type, bool) {
+	return nil, false
+}
+
+func (it *Iterate) computeSeq(state *est.State) unsafe.Pointer {
+	return it.empty(state)
+}
@@ -1,41 +1,109 @@
 package velty
 
 import (
+	"github.com/viant/velty/ast"
 	"github.com/viant/velty/est"
 	"github.com/viant/velty/est/op"
 	"github.com/viant/velty/parser"
+	"strings"
 	"unsafe"
 )
 
 type evaluator struct {
-	x       *op.Operand
-	cache   *cache
-	control est.Control
-	parent  *Planner
+	x         *op.Operand
+	cache     *cache
+	control   est.Control
+	parent    *Planner
+	loader    Loader
+	parsing   []string
+	keyPrefix string
+	location  *ast.Location
 }
 
 func (e *evaluator) compute(state *est.State) unsafe.Pointer {
 	varValue := *(*string)(e.x.Exec(state))
-	if cacheValue, ok := e.cache.expression(varValue); ok {
-		newState := e.newState(cacheValue.planner, state)
-		return cacheValue.compute(newState)
+	key := varValue
+	if e.loader != nil {
+		if err := e.checkParseCycle(varValue); err != nil {
+			e.reportError(state, err)
+			return est.EmptyStringPtr
+		}
+		key = e.keyPrefix + varValue
+	}
+
+	if cacheValue, ok := e.cache.expression(key); ok {
+		return e.exec(cacheValue.planner, cacheValue.compute, state)
 	}
 
-	block, err := parser.Parse([]byte(varValue))
+	template, err := e.template(varValue)
 	if err != nil {
+		e.reportError(state, err)
+		return est.EmptyStringPtr
+	}
+
+	source := &ast.Source{Input: template}
+	if e.loader != nil {
+		source.Name = varValue
+	}
+
+	block, err := parser.ParseTemplate(source.Name, template, e.parent.spaceGobbling)
+	if err != nil {
+		e.reportError(state, err)
 		return est.EmptyStringPtr
 	}
 
 	evaluatorPlanner := e.parent.New()
+	evaluatorPlanner.source = source
+	if e.loader != nil {
+		evaluatorPlanner.parsing = append(append([]string{}, e.parsing...), varValue)
+	}
+
 	exec, err := evaluatorPlanner.newCompute(block)
 	if err != nil {
+		e.reportError(state, err)
 		return est.EmptyStringPtr
 	}
 
-	newState := e.newState(evaluatorPlanner, state)
-	e.cache.put(varValue, evaluatorPlanner, exec)
+	e.cache.put(key, evaluatorPlanner, exec)
+	return e.exec(evaluatorPlanner, exec, state)
+}
 
-	return exec(newState)
+func (e *evaluator) exec(planner *Planner, compute est.Compute, state *est.State) unsafe.Pointer {
+	newState := e.newState(planner, state)
+	result := compute(newState)
+	if newState.Control == est.HasStop {
+		state.Control = est.HasStop
+	}
+
+	if len(newState.Errors) > 0 {
+		state.Errors = append(state.Errors, newState.Errors...)
+	}
+	return result
+}
+
+//reportError adds #parse errors to the state, #evaluate errors are ignored
+func (e *evaluator) reportError(state *est.State, err error) {
+	if e.loader != nil {
+		state.AddError(e.location.WithError(err))
+	}
+}
+
+//template returns evaluated template, in case of the #parse value is a template name resolved with the loader
+func (e *evaluator) template(value string) ([]byte, error) {
+	if e.loader == nil {
+		return []byte(value), nil
+	}
+	return e.parent.load(value)
+}
+
+//checkParseCycle checks the #parse chain ahead of the cache lookup, as cached template can #parse itself again
+func (e *evaluator) checkParseCycle(value string) error {
+	for _, parsed := range e.parsing {
+		if parsed == value {
+			return parseCycleError(e.parsing, value)
+		}
+	}
+	return nil
 }
 
 func (e *evaluator) newState(planner *Planner, state *est.State) *est.State {
@@ -50,6 +118,20 @@ func (e *evaluator) newState(planner *Planner, state *est.State) *est.State {
 }
 
 func evaluate(expr *op.Expression, cache *cache, parent *Planner) (est.New, error) {
+	return newEvaluator(expr, cache, parent, nil)
+}
+
+func newEvaluator(expr *op.Expression, cache *cache, parent *Planner, loader Loader) (est.New, error) {
+	var parsing []string
+	var location *ast.Location
+	var keyPrefix string
+	if loader != nil {
+		parsing = append(parsing, parent.parsing...)
+		location = parent.location("")
+		//template compiled for a #parse chain is cached per chain, so that nested #parse cycle is detected for each of them
+		keyPrefix = parseKeyPrefix + strings.Join(parsing, " -> ") + " -> "
+	}
+
 	return func(control est.Control) (est.Compute, error) {
 		x, err := expr.Operand(control)
 		if err != nil {
@@ -57,10 +139,14 @@ func evaluate(expr *op.Expression, cache *cache, parent *Planner) (est.New, erro
 		}
 
 		return (&evaluator{
-			x:       x,
-			cache:   cache,
-			control: control,
-			parent:  parent,
+			x:         x,
+			cache:     cache,
+			control:   control,
+			parent:    parent,
+			loader:    loader,
+			parsing:   parsing,
+			keyPrefix: keyPrefix,
+			location:  location,
 		}).compute, nil
 	}, nil
 }
@@ -12,7 +12,7 @@ func (p *Planner) compileExpr(e ast.Expression) (*op.Expression, error) {
 	case *expr.Literal:
 		return p.literalExpr(actual)
 	case *expr.Select:
-		return p.selectorExpr(actual)
+		return p.referenceExpr(actual)
 	case *expr.Binary:
 		return p.compileBinary(actual)
 	case *expr.Unary:
@@ -21,6 +21,12 @@ func (p *Planner) compileExpr(e ast.Expression) (*op.Expression, error) {
 		return p.compileExpr(actual.P)
 	case *expr.Range:
 		return p.compileRange(actual)
+	case *expr.List:
+		return p.compileList(actual)
+	case *expr.Map:
+		return p.compileMap(actual)
+	case *expr.Ternary:
+		return p.compileTernary(actual)
 	}
 
 	return nil, fmt.Errorf("unsupported expr: %T", e)
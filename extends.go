@@ -0,0 +1,126 @@
+package velty
+
+import (
+	"fmt"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/parser"
+	"strings"
+)
+
+//sourced represents statement compiled within its own template source, i.e. #block section of the extending template merged into the layout
+type sourced struct {
+	statement ast.Statement
+	source    *ast.Source
+}
+
+//extend merges the template with the layouts it extends, #block sections of the extending template override the layout ones.
+//Top level #set, #macro, #param and #var of the extending template are compiled ahead of the layout, text outside of #block is ignored,
+//other directives and references outside of #block are reported as error.
+func (p *Planner) extend(root *stmt.Block) (*stmt.Block, error) {
+	extends, ok := extendsOf(root)
+	if !ok {
+		return root, nil
+	}
+
+	sections := map[string]*sourced{}
+	var preamble []ast.Statement
+	source := p.source
+	extending := []string{source.Name}
+	for ok {
+		name, isLiteral := literalName(extends.X)
+		if !isLiteral {
+			return nil, source.NewError(&extends.Pos, fmt.Errorf("failed to compile #extends, expected template name literal"))
+		}
+
+		for _, statement := range root.Stmt {
+			switch actual := statement.(type) {
+			case *stmt.Extends, *stmt.Append:
+			case *stmt.NamedBlock:
+				addSections(sections, actual, source)
+			case *stmt.Statement, *stmt.Macro, *stmt.Param:
+				preamble = append(preamble, &sourced{statement: statement, source: source})
+			default:
+				var pos *ast.Pos
+				if locatable, ok := statement.(ast.Locatable); ok {
+					pos = locatable.Position()
+				}
+				return nil, source.NewError(pos, fmt.Errorf("unsupported output outside of #block in the extending template"))
+			}
+		}
+
+		for _, extended := range extending {
+			if extended == name {
+				return nil, source.NewError(&extends.Pos, fmt.Errorf("detected #extends cycle: %v -> %v", strings.Join(extending, " -> "), name))
+			}
+		}
+
+		template, err := p.load(name)
+		if err != nil {
+			return nil, source.NewError(&extends.Pos, err)
+		}
+
+		if root, err = parser.ParseTemplate(name, template, p.spaceGobbling); err != nil {
+			return nil, err
+		}
+
+		source = &ast.Source{Name: name, Input: template}
+		extending = append(extending, name)
+		extends, ok = extendsOf(root)
+	}
+
+	p.sections = sections
+	return &stmt.Block{Stmt: append(preamble, &sourced{statement: root, source: source})}, nil
+}
+
+func extendsOf(root *stmt.Block) (*stmt.Extends, bool) {
+	for _, statement := range root.Stmt {
+		if extends, ok := statement.(*stmt.Extends); ok {
+			return extends, true
+		}
+	}
+	return nil, false
+}
+
+//addSections adds section with its nested sections, sections added by the more specific template take precedence
+func addSections(sections map[string]*sourced, section *stmt.NamedBlock, source *ast.Source) {
+	if _, ok := sections[section.Name]; !ok {
+		sections[section.Name] = &sourced{statement: section, source: source}
+	}
+
+	for _, statement := range section.Body.Stmt {
+		if nested, ok := statement.(*stmt.NamedBlock); ok {
+			addSections(sections, nested, source)
+		}
+	}
+}
+
+func (p *Planner) compileNamedBlock(actual *stmt.NamedBlock) (est.New, error) {
+	if section, ok := p.sections[actual.Name]; ok {
+		delete(p.sections, actual.Name)
+		defer func() { p.sections[actual.Name] = section }()
+		return p.compileSourced(section)
+	}
+	return p.compileBlock(&actual.Body)
+}
+
+func (p *Planner) compileSourced(actual *sourced) (est.New, error) {
+	source, pos := p.source, p.pos
+	p.source = actual.source
+	defer func() { p.source, p.pos = source, pos }()
+
+	if section, ok := actual.statement.(*stmt.NamedBlock); ok {
+		return p.compileBlock(&section.Body)
+	}
+
+	if locatable, ok := actual.statement.(ast.Locatable); ok {
+		p.pos = locatable.Position()
+	}
+
+	result, err := p.compileStmt(actual.statement)
+	if err != nil {
+		return nil, p.locate(actual.statement, err)
+	}
+	return result, nil
+}
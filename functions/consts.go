@@ -12,6 +12,11 @@ var FuncTime = registryInstance.DefineNs("time", NewEntry(
 	NewFunctionNamespace(reflect.TypeOf(&Time{})),
 ))
 
+var FuncDate = registryInstance.DefineNs("date", NewEntry(
+	&Date{},
+	NewFunctionNamespace(reflect.TypeOf(&Date{})),
+))
+
 var FuncTypes = registryInstance.DefineNs("types", NewEntry(
 	&Types{},
 	NewFunctionNamespace(reflect.TypeOf(&Types{})),
@@ -0,0 +1,144 @@
+package functions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Date represents Velocity DateTool alike functions, using Java SimpleDateFormat patterns, i.e. $date.format('yyyy-MM-dd', $t)
+type Date struct{}
+
+func (d Date) Format(pattern string, value time.Time) (string, error) {
+	layout, err := JavaLayout(pattern)
+	if err != nil {
+		return "", err
+	}
+	return value.Format(layout), nil
+}
+
+func (d Date) Parse(pattern, value string) (time.Time, error) {
+	layout, err := JavaLayout(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(layout, value)
+}
+
+func (d Date) ParseInLocation(pattern, value, location string) (time.Time, error) {
+	layout, err := JavaLayout(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return Time{}.ParseInLocation(layout, value, location)
+}
+
+//javaLayouts maps Java SimpleDateFormat pattern letters repetitions to the go layout elements
+var javaLayouts = map[string]string{
+	"yyyy": "2006", "yyy": "2006", "yy": "06", "y": "2006",
+	"MMMM": "January", "MMM": "Jan", "MM": "01", "M": "1",
+	"dd": "02", "d": "2",
+	"EEEE": "Monday", "EEE": "Mon", "EE": "Mon", "E": "Mon",
+	"HH": "15", "H": "15",
+	"hh": "03", "h": "3",
+	"mm": "04", "m": "4",
+	"ss": "05", "s": "5",
+	"a": "PM",
+	"z": "MST", "zz": "MST", "zzz": "MST", "zzzz": "MST", "Z": "-0700",
+	"XXX": "Z07:00", "XX": "Z0700", "X": "Z07",
+}
+
+//maxFractionDigits represents the longest fraction of second go layout supports
+const maxFractionDigits = 9
+
+//literalProbe formats every go layout element differently than it is spelled, so that literal text formatted with it changes only if go reads it as layout
+var literalProbe = time.Date(1999, 12, 31, 11, 59, 58, 987654321, time.FixedZone("XYZ", 90*60))
+
+//JavaLayout translates Java SimpleDateFormat pattern to the go time layout, i.e. yyyy-MM-dd'T'HH:mm:ss to 2006-01-02T15:04:05,
+//go layout can not escape literal text, thus literal text go would read as layout elements is reported as error
+func JavaLayout(pattern string) (string, error) {
+	var layout, literal strings.Builder
+	flushLiteral := func() error {
+		text := literal.String()
+		literal.Reset()
+		if literalProbe.Format(text) != text {
+			return fmt.Errorf("unsupported literal text %q in the date pattern %q", text, pattern)
+		}
+		layout.WriteString(text)
+		return nil
+	}
+
+	for i := 0; i < len(pattern); {
+		ch := pattern[i]
+		switch {
+		case ch == '\'':
+			i = quotedText(pattern, i+1, &literal)
+		case (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z'):
+			j := i
+			for j < len(pattern) && pattern[j] == ch {
+				j++
+			}
+
+			element, ok := javaElement(ch, j-i)
+			if !ok {
+				return "", fmt.Errorf("unsupported letter %q in the date pattern %q", ch, pattern)
+			}
+
+			if err := flushLiteral(); err != nil {
+				return "", err
+			}
+			layout.WriteString(element)
+			i = j
+		default:
+			literal.WriteByte(ch)
+			i++
+		}
+	}
+
+	if err := flushLiteral(); err != nil {
+		return "", err
+	}
+	return layout.String(), nil
+}
+
+//javaElement returns go layout element for the pattern letter repeated count times, 4 or more repetitions of text letters
+//represent the full form, numbers longer than supported by go layout use the longest one
+func javaElement(letter byte, count int) (string, bool) {
+	if letter == 'S' {
+		if count > maxFractionDigits {
+			count = maxFractionDigits
+		}
+		return strings.Repeat("0", count), true
+	}
+
+	for ; count > 0; count-- {
+		if element, ok := javaLayouts[strings.Repeat(string(letter), count)]; ok {
+			return element, true
+		}
+	}
+	return "", false
+}
+
+// quotedText writes Java pattern quoted text starting at i, where ” represents a single quote, returns position after the closing quote
+func quotedText(pattern string, i int, literal *strings.Builder) int {
+	if i < len(pattern) && pattern[i] == '\'' {
+		literal.WriteByte('\'')
+		return i + 1
+	}
+
+	for i < len(pattern) {
+		if pattern[i] != '\'' {
+			literal.WriteByte(pattern[i])
+			i++
+			continue
+		}
+
+		if i+1 < len(pattern) && pattern[i+1] == '\'' {
+			literal.WriteByte('\'')
+			i += 2
+			continue
+		}
+		return i + 1
+	}
+	return i
+}
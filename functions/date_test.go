@@ -0,0 +1,120 @@
+package functions
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestJavaLayout(t *testing.T) {
+	testCases := []struct {
+		description string
+		pattern     string
+		expect      string
+		expectErr   bool
+	}{
+		{
+			description: "date",
+			pattern:     "yyyy-MM-dd",
+			expect:      "2006-01-02",
+		},
+		{
+			description: "quoted literal",
+			pattern:     "yyyy-MM-dd'T'HH:mm:ss.SSSXXX",
+			expect:      "2006-01-02T15:04:05.000Z07:00",
+		},
+		{
+			description: "escaped quote",
+			pattern:     "h 'o''clock' a",
+			expect:      "3 o'clock PM",
+		},
+		{
+			description: "names",
+			pattern:     "EEEE, MMMM d yy z",
+			expect:      "Monday, January 2 06 MST",
+		},
+		{
+			description: "full text form",
+			pattern:     "EEEEE MMMMM",
+			expect:      "Monday January",
+		},
+		{
+			description: "long numbers",
+			pattern:     "yyyyy-MMM-ddd SSSS",
+			expect:      "2006-Jan-02 0000",
+		},
+		{
+			description: "safe quoted literal",
+			pattern:     "'Today is' EEEE",
+			expect:      "Today is Monday",
+		},
+		{
+			description: "quoted number",
+			pattern:     "'Day 1' yyyy",
+			expectErr:   true,
+		},
+		{
+			description: "quoted layout word",
+			pattern:     "yyyy 'on Monday'",
+			expectErr:   true,
+		},
+		{
+			description: "stray digits",
+			pattern:     "yyyy-MM-dd 12",
+			expectErr:   true,
+		},
+		{
+			description: "unsupported letter",
+			pattern:     "yyyy-MM-ddTHH",
+			expectErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		layout, err := JavaLayout(testCase.pattern)
+		if testCase.expectErr {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+
+		assert.Nil(t, err, testCase.description)
+		assert.Equal(t, testCase.expect, layout, testCase.description)
+	}
+}
+
+func TestDate_Format(t *testing.T) {
+	at := time.Date(2024, 5, 6, 14, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		description string
+		pattern     string
+		expect      string
+		expectErr   bool
+	}{
+		{
+			description: "full month and day names",
+			pattern:     "EEEEE, MMMMM d",
+			expect:      "Monday, May 6",
+		},
+		{
+			description: "quoted literal",
+			pattern:     "'at' h:mm a",
+			expect:      "at 2:30 PM",
+		},
+		{
+			description: "literal read as layout",
+			pattern:     "'Day 1' yyyy",
+			expectErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := Date{}.Format(testCase.pattern, at)
+		if testCase.expectErr {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+
+		assert.Nil(t, err, testCase.description)
+		assert.Equal(t, testCase.expect, actual, testCase.description)
+	}
+}
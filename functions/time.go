@@ -36,3 +36,31 @@ func (t Time) DiscoverInterfaces(aFunc interface{}) (func(args ...interface{}) (
 func (t Time) Now() time.Time {
 	return Now()
 }
+
+func (t Time) Parse(layout, value string) (time.Time, error) {
+	return time.Parse(layout, value)
+}
+
+func (t Time) ParseInLocation(layout, value, location string) (time.Time, error) {
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(layout, value, loc)
+}
+
+func (t Time) In(value time.Time, location string) (time.Time, error) {
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return value.In(loc), nil
+}
+
+func (t Time) ParseDuration(value string) (time.Duration, error) {
+	return time.ParseDuration(value)
+}
+
+func (t Time) Unix(sec int) time.Time {
+	return time.Unix(int64(sec), 0)
+}
@@ -19,6 +19,12 @@ func (p *Planner) literalExpr(literal *expr.Literal) (*op.Expression, error) {
 		ptr := unsafe.Pointer(iPtr)
 		expr.Type = reflect.TypeOf(i)
 		expr.LiteralPtr = &ptr
+	case reflect.Uint64:
+		u, _ := strconv.ParseUint(literal.Value, 10, 64)
+		p.constants.add(&u)
+		expr.Type = reflect.TypeOf(u)
+		ptr := unsafe.Pointer(&u)
+		expr.LiteralPtr = &ptr
 	case reflect.Float64:
 		f, _ := strconv.ParseFloat(literal.Value, 64)
 		p.constants.add(&f)
@@ -0,0 +1,26 @@
+package velty
+
+import (
+	"io/fs"
+	"strings"
+)
+
+type (
+	//Loader loads templates and resources referenced by #parse and #include
+	Loader interface {
+		Load(name string) ([]byte, error)
+	}
+
+	fsLoader struct {
+		fs fs.FS
+	}
+)
+
+func (l *fsLoader) Load(name string) ([]byte, error) {
+	return fs.ReadFile(l.fs, strings.TrimPrefix(name, "/"))
+}
+
+//NewFSLoader creates Loader reading templates from the given file system i.e. os.DirFS or embed.FS
+func NewFSLoader(fileSystem fs.FS) Loader {
+	return &fsLoader{fs: fileSystem}
+}
@@ -0,0 +1,158 @@
+package velty
+
+import (
+	"github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	estmt "github.com/viant/velty/est/stmt"
+	"reflect"
+)
+
+const (
+	foreachVariable       = "foreach"
+	velocityCountVariable = "velocityCount"
+)
+
+var (
+	loopType = reflect.TypeOf(estmt.Loop{})
+	intType  = reflect.TypeOf(0)
+)
+
+//loopScope represents $foreach and $velocityCount variables visible while compiling loop body
+type loopScope struct {
+	used          bool
+	loop          *op.Selector
+	parent        *op.Selector
+	velocityCount *op.Selector
+	bindings      []*binding
+}
+
+//enterLoop binds loop metadata variables, shadowing the ones of the enclosing loop
+func (p *Planner) enterLoop() (*loopScope, error) {
+	scope := &loopScope{}
+	if parent := p.selectorByName(foreachVariable); parent != nil && parent.Type == loopType {
+		scope.parent = parent
+	}
+
+	aBinding, loop, err := p.bind(foreachVariable, loopType)
+	if err != nil {
+		return nil, err
+	}
+	scope.loop = loop
+	scope.bindings = append(scope.bindings, aBinding)
+
+	aBinding, velocityCount, err := p.bind(velocityCountVariable, intType)
+	if err != nil {
+		p.unbind(scope.bindings[0])
+		return nil, err
+	}
+	scope.velocityCount = velocityCount
+	scope.bindings = append(scope.bindings, aBinding)
+
+	p.loops = append(p.loops, scope)
+	return scope, nil
+}
+
+//exitLoop restores enclosing loop variables, returns loop metadata if loop body referenced it
+func (p *Planner) exitLoop(scope *loopScope) *estmt.LoopMeta {
+	for i := len(scope.bindings) - 1; i >= 0; i-- {
+		p.unbind(scope.bindings[i])
+	}
+
+	p.loops = p.loops[:len(p.loops)-1]
+	if !scope.used {
+		return nil
+	}
+
+	if len(p.loops) > 0 && scope.parent != nil {
+		p.loops[len(p.loops)-1].used = true
+	}
+
+	return &estmt.LoopMeta{
+		Loop:          scope.loop,
+		Parent:        scope.parent,
+		VelocityCount: scope.velocityCount,
+	}
+}
+
+//innerLoop returns the innermost loop scope, macro body referencing $foreach is bound to it
+func (p *Planner) innerLoop() *loopScope {
+	if len(p.loops) == 0 {
+		return nil
+	}
+	return p.loops[len(p.loops)-1]
+}
+
+//markLoopUsage flags innermost loop metadata as used if id refers to it
+func (p *Planner) markLoopUsage(id string) {
+	if len(p.loops) > 0 && (id == foreachVariable || id == velocityCountVariable) {
+		p.loops[len(p.loops)-1].used = true
+	}
+}
+
+//markLoopsUsed flags all loops metadata as used, i.e. when it could be referenced by dynamically evaluated template
+func (p *Planner) markLoopsUsed() {
+	for _, scope := range p.loops {
+		scope.used = true
+	}
+}
+
+//compileLoopBody compiles #foreach body with $foreach and $velocityCount variables in scope,
+//returns control flags the loop has to be compiled with
+func (p *Planner) compileLoopBody(body *stmt.Block) (est.New, *estmt.LoopMeta, est.Control, error) {
+	scope, err := p.enterLoop()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	block, control, err := p.compileControlled(func() (est.New, error) {
+		return p.compileBlock(body)
+	}, est.HasStop)
+	meta := p.exitLoop(scope)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return block, meta, control, nil
+}
+
+//compileItemBody compiles #foreach item and body, with BlockScoping item and variables assigned in the body are local to the loop
+func (p *Planner) compileItemBody(actual *stmt.ForEach, itemType reflect.Type) (*op.Expression, est.New, *estmt.LoopMeta, est.Control, error) {
+	scope := p.enterScope()
+	defer p.exitScope(scope)
+
+	if err := p.defineLocal(actual.Item.ID, itemType); err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	item, err := p.compileExpr(actual.Item)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	block, meta, control, err := p.compileLoopBody(&actual.Body)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+	return item, block, meta, control, nil
+}
+
+//compileControlled compiles loop or macro body with its own #break, #continue and #stop usage flags, so that only blocks
+//using these directives are compiled as interruptible, flags listed in propagate are passed to the enclosing scope
+func (p *Planner) compileControlled(compile func() (est.New, error), propagate est.Control) (est.New, est.Control, error) {
+	outer := *p.Control
+	*p.Control = 0
+	body, err := compile()
+	control := *p.Control
+	*p.Control = outer | control&propagate
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, control, nil
+}
+
+//withControl creates statement compiled with control flags of its own body, regardless of the enclosing scope ones
+func withControl(aNew est.New, control est.Control) est.New {
+	return func(_ est.Control) (est.Compute, error) {
+		return aNew(control)
+	}
+}
@@ -0,0 +1,301 @@
+package velty
+
+import (
+	"fmt"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+	"github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	estmt "github.com/viant/velty/est/stmt"
+	"github.com/viant/velty/est/stmt/assign"
+	"github.com/viant/xunsafe/converter"
+	"reflect"
+	"strings"
+)
+
+const bodyContent = "bodyContent"
+
+//macroPropagated control flags used by the macro body passed to the calling scope, #break exits the macro itself
+const macroPropagated = est.HasContinue | est.HasStop
+
+var computeType = reflect.TypeOf(est.Compute(nil))
+
+type (
+	macro struct {
+		def       *stmt.Macro
+		source    *ast.Source
+		instances []*macroInstance
+		compiling bool
+	}
+
+	//macroInstance represents macro body planned for the given arguments types within the enclosing loop
+	macroInstance struct {
+		types   []reflect.Type
+		isBlock bool
+		loop    *loopScope
+		params  []*op.Selector
+		content *op.Selector
+		body    est.New
+		control est.Control
+	}
+
+	//binding represents variable visible only while compiling macro body, loop body or block scope
+	binding struct {
+		name   string
+		hidden map[string]int
+		bound  map[string]int
+	}
+)
+
+func (m *macro) instance(types []reflect.Type, isBlock bool, loop *loopScope) *macroInstance {
+	for _, candidate := range m.instances {
+		if candidate.matches(types, isBlock, loop) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func (i *macroInstance) matches(types []reflect.Type, isBlock bool, loop *loopScope) bool {
+	if i.isBlock != isBlock || i.loop != loop || len(i.types) != len(types) {
+		return false
+	}
+
+	for j, rType := range types {
+		if i.types[j] != rType {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Planner) registerMacros(root *stmt.Block) {
+	for _, statement := range root.Stmt {
+		if def, ok := statement.(*stmt.Macro); ok {
+			p.registerMacro(def)
+		}
+	}
+}
+
+func (p *Planner) registerMacro(def *stmt.Macro) {
+	if registered, ok := p.macros[def.Name]; ok && registered.def == def {
+		return
+	}
+	p.macros[def.Name] = &macro{def: def, source: p.source}
+}
+
+//macrosSnapshot copies macro definitions without planned instances, since these are bound to the planner Type
+func (p *Planner) macrosSnapshot() map[string]*macro {
+	result := make(map[string]*macro, len(p.macros))
+	for name, aMacro := range p.macros {
+		result[name] = &macro{def: aMacro.def, source: aMacro.source}
+	}
+	return result
+}
+
+func (p *Planner) compileMacro(def *stmt.Macro) (est.New, error) {
+	p.registerMacro(def)
+	return nop(), nil
+}
+
+func (p *Planner) compileMacroCall(call *stmt.MacroCall, isBlock bool) (est.New, error) {
+	aMacro, ok := p.macros[call.Name]
+	if !ok {
+		if isBlock {
+			return nil, fmt.Errorf("undefined block macro %v", call.Name)
+		}
+		return p.compileAppend(stmt.NewAppend(call.Raw))
+	}
+
+	if len(call.Args) > len(aMacro.def.Params) {
+		return nil, fmt.Errorf("macro %v expects at most %v arguments, but got %v", call.Name, len(aMacro.def.Params), len(call.Args))
+	}
+
+	args := make([]*op.Expression, len(call.Args))
+	types := make([]reflect.Type, len(call.Args))
+	var err error
+	for i, arg := range call.Args {
+		if args[i], err = p.compileExpr(arg); err != nil {
+			return nil, err
+		}
+		types[i] = args[i].Type
+	}
+
+	instance, err := p.macroInstance(aMacro, types, isBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]est.New, 0, len(args))
+	var params, saved []*op.Selector
+	for i, arg := range args {
+		param := instance.params[i]
+		if param == nil {
+			continue
+		}
+
+		params = append(params, param)
+		saved = append(saved, p.accumulator(param.Type))
+
+		x := op.NewExpression(param)
+		x.Type = param.Type
+		unify, err := converter.Unify(x.Type, arg.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		arg.Unify = unify.Y
+		arg.Type = unify.RType
+		assignment, err := assign.Assign(x, arg)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if !isBlock {
+		return estmt.NewMacroCall(assignments, instance.body, params, saved, nil, nil), nil
+	}
+
+	content, err := p.compileBlock(call.Body)
+	if err != nil {
+		return nil, err
+	}
+	return estmt.NewMacroCall(assignments, instance.body, params, saved, content, instance.content), nil
+}
+
+func (p *Planner) macroInstance(aMacro *macro, types []reflect.Type, isBlock bool) (*macroInstance, error) {
+	loop := p.innerLoop()
+	if instance := aMacro.instance(types, isBlock, loop); instance != nil {
+		*p.Control |= instance.control & macroPropagated
+		return instance, nil
+	}
+
+	if aMacro.compiling {
+		return nil, fmt.Errorf("recursive macro %v invocation is not supported", aMacro.def.Name)
+	}
+
+	aMacro.compiling = true
+	defer func() { aMacro.compiling = false }()
+
+	instance := &macroInstance{types: types, isBlock: isBlock, loop: loop, params: make([]*op.Selector, len(types))}
+	var bindings []*binding
+	defer func() {
+		for i := len(bindings) - 1; i >= 0; i-- {
+			p.unbind(bindings[i])
+		}
+	}()
+
+	scope := p.enterScope()
+	defer p.exitScope(scope)
+
+	for i, param := range aMacro.def.Params {
+		var paramType reflect.Type
+		if i < len(types) {
+			paramType = types[i]
+		}
+
+		aBinding, selector, err := p.bind(param.ID, paramType)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, aBinding)
+		p.declareLocal(param.ID)
+		if i < len(types) {
+			instance.params[i] = selector
+		}
+	}
+
+	if isBlock {
+		aBinding, selector, err := p.bind(bodyContent, computeType)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, aBinding)
+		p.declareLocal(bodyContent)
+		instance.content = selector
+	}
+
+	source := p.source
+	p.source = aMacro.source
+	body, control, err := p.compileControlled(func() (est.New, error) {
+		return p.compileBlock(&aMacro.def.Body)
+	}, macroPropagated)
+	p.source = source
+	if err != nil {
+		return nil, err
+	}
+
+	instance.body = withControl(body, control)
+	instance.control = control
+	aMacro.instances = append(aMacro.instances, instance)
+	return instance, nil
+}
+
+//bind hides all selectors with given name, and if rType is specified, defines new variable visible with that name
+func (p *Planner) bind(name string, rType reflect.Type) (*binding, *op.Selector, error) {
+	result := &binding{name: name, hidden: map[string]int{}, bound: map[string]int{}}
+	prefix := name + fieldSeparator
+	for id, index := range p.selectors.Index {
+		if id == name || strings.HasPrefix(id, prefix) {
+			result.hidden[id] = index
+			delete(p.selectors.Index, id)
+		}
+	}
+
+	p.bindings = append(p.bindings, result)
+	if rType == nil {
+		return result, nil, nil
+	}
+
+	offset := len(p.selectors.Selectors())
+	fieldName := p.newName()
+	field := p.Type.AddField(fieldName, fieldName, rType)
+	if err := p.addSelectors("", field, name); err != nil {
+		p.unbind(result)
+		return nil, nil, err
+	}
+
+	for i, selector := range p.selectors.Selectors()[offset:] {
+		result.bound[selector.ID] = offset + i
+	}
+	return result, p.selectorByName(name), nil
+}
+
+//unbind restores selectors hidden by the binding
+func (p *Planner) unbind(aBinding *binding) {
+	aBinding.restore(p.selectors)
+	for i := len(p.bindings) - 1; i >= 0; i-- {
+		if p.bindings[i] == aBinding {
+			p.bindings = append(p.bindings[:i], p.bindings[i+1:]...)
+			break
+		}
+	}
+}
+
+func (b *binding) restore(selectors *op.Selectors) {
+	for id, index := range b.bound {
+		if selectors.Index[id] == index {
+			delete(selectors.Index, id)
+		}
+	}
+
+	for id, index := range b.hidden {
+		selectors.Index[id] = index
+	}
+}
+
+func (p *Planner) isBodyContent(actual *expr.Select) (*op.Selector, bool) {
+	if actual.ID != bodyContent || actual.X != nil {
+		return nil, false
+	}
+
+	selector := p.selectorByName(bodyContent)
+	if selector == nil || selector.Type != computeType {
+		return nil, false
+	}
+	return selector, true
+}
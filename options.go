@@ -2,6 +2,7 @@ package velty
 
 import (
 	"github.com/viant/velty/functions"
+	"github.com/viant/velty/parser"
 )
 
 //Option represents Planner generic option
@@ -19,5 +20,74 @@ type EscapeHTML bool
 //PanicOnError panics and recover when first error returned.
 type PanicOnError bool
 
+//ReferenceMode represents rendering mode of the unresolved references i.e. $foo where foo was not defined
+type ReferenceMode int
+
+const (
+	//LiteralReferences renders unresolved references as they were defined in the template (Velocity default)
+	LiteralReferences ReferenceMode = iota
+	//EmptyReferences renders unresolved references as an empty string
+	EmptyReferences
+	//StrictReferences fails Compile on any unresolved reference
+	StrictReferences
+)
+
+//SortMapKeys iterates maps in #foreach in sorted key order, which makes the output deterministic
+type SortMapKeys bool
+
+//SpaceGobbling represents Velocity 2 whitespace gobbling mode around directives
+type SpaceGobbling = parser.SpaceGobbling
+
+const (
+	//GobbleNone keeps all whitespace as it was defined in the template (default)
+	GobbleNone = parser.GobbleNone
+	//GobbleBC gobbles new line directly following a directive, and indentation of the #set lines (Velocity 1.x compatible)
+	GobbleBC = parser.GobbleBC
+	//GobbleLines gobbles indentation and new line of the lines holding only directives or comments
+	GobbleLines = parser.GobbleLines
+	//GobbleStructured gobbles like GobbleLines, and additionally removes block directive extra indentation from its body
+	GobbleStructured = parser.GobbleStructured
+)
+
+//PropertyResolution represents strategy of resolving template properties i.e. $customer.firstName
+type PropertyResolution int
+
+const (
+	//ExactProperties matches properties with the exact Go field or velty tag names (default)
+	ExactProperties PropertyResolution = iota
+	//JavaBeanProperties matches fields ignoring first letter case, then zero argument GetX(), IsX() and X() methods
+	JavaBeanProperties
+)
+
+//Truthiness represents evaluation mode of the non bool conditions i.e. #if($name), #if(!$items)
+type Truthiness int
+
+const (
+	//VelocityTruthiness evaluates null, false, empty string, empty collection and zero number as false (default)
+	VelocityTruthiness Truthiness = iota
+	//StrictTruthiness fails Compile on non bool conditions
+	StrictTruthiness
+)
+
+//ZeroIsTrue evaluates zero numbers as true with VelocityTruthiness, like Velocity 1.x did
+type ZeroIsTrue bool
+
+//VariableScoping represents visibility of the variables assigned with #set
+type VariableScoping int
+
+const (
+	//GlobalScoping keeps all variables in the template scope, #set inside #foreach or macro body updates outer variable (Velocity default)
+	GlobalScoping VariableScoping = iota
+	//BlockScoping gives #foreach and macro bodies lexical scope, #set defines variable local to the body, shadowing outer one,
+	//$global reference escapes to the template scope i.e. #set($global.total = $total + $item.Price)
+	BlockScoping
+)
+
+//TimeLayout represents layout used to render time.Time values, without it time.Time is rendered as JSON value i.e. "2014-11-12T11:45:26Z"
+type TimeLayout string
+
+//TemplateName represents template name reported in errors
+type TemplateName string
+
 //TypeParser parses type string representation into reflect.Type
 type TypeParser = functions.TypeParser
@@ -0,0 +1,115 @@
+package velty
+
+import (
+	"fmt"
+	stmt2 "github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/est"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//Input represents template input declared with #param or #var
+type Input struct {
+	Name     string
+	Type     reflect.Type
+	Required bool
+}
+
+var builtinTypes = map[string]reflect.Type{
+	"int":           reflect.TypeOf(0),
+	"int8":          reflect.TypeOf(int8(0)),
+	"int16":         reflect.TypeOf(int16(0)),
+	"int32":         reflect.TypeOf(int32(0)),
+	"int64":         reflect.TypeOf(int64(0)),
+	"uint":          reflect.TypeOf(uint(0)),
+	"uint8":         reflect.TypeOf(uint8(0)),
+	"uint16":        reflect.TypeOf(uint16(0)),
+	"uint32":        reflect.TypeOf(uint32(0)),
+	"uint64":        reflect.TypeOf(uint64(0)),
+	"float32":       reflect.TypeOf(float32(0)),
+	"float64":       reflect.TypeOf(0.0),
+	"string":        reflect.TypeOf(""),
+	"bool":          reflect.TypeOf(false),
+	"byte":          reflect.TypeOf(byte(0)),
+	"rune":          reflect.TypeOf(rune(0)),
+	"interface{}":   reflect.TypeOf((*interface{})(nil)).Elem(),
+	"any":           reflect.TypeOf((*interface{})(nil)).Elem(),
+	"time.Time":     reflect.TypeOf(time.Time{}),
+	"time.Duration": reflect.TypeOf(time.Duration(0)),
+}
+
+//Inputs returns inputs declared with #param or #var by the last compiled template, in the declaration order
+func (p *Planner) Inputs() []*Input {
+	return p.inputs
+}
+
+func (p *Planner) compileParam(actual *stmt2.Param) (est.New, error) {
+	rType, err := p.resolveType(actual.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare $%v: %w", actual.Name, err)
+	}
+
+	if selector, ok := p.selectors.Index[actual.Name]; ok {
+		if defined := p.selectors.Selector(selector).Type; defined != rType {
+			return nil, fmt.Errorf("failed to declare $%v as %v, variable was already defined as %v", actual.Name, rType.String(), defined.String())
+		}
+	} else if err = p.DefineVariable(actual.Name, rType); err != nil {
+		return nil, err
+	}
+
+	p.inputs = append(p.inputs, &Input{Name: actual.Name, Type: rType, Required: actual.Required})
+	return nop(), nil
+}
+
+//resolveType resolves builtin types, slices, pointers and maps of them, other types are resolved with the TypeParser option
+func (p *Planner) resolveType(typeName string) (reflect.Type, error) {
+	typeName = strings.TrimSpace(typeName)
+	if rType, ok := builtinTypes[typeName]; ok {
+		return rType, nil
+	}
+
+	switch {
+	case strings.HasPrefix(typeName, "[]"):
+		elem, err := p.resolveType(typeName[2:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+
+	case strings.HasPrefix(typeName, "*"):
+		elem, err := p.resolveType(typeName[1:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PtrTo(elem), nil
+
+	case strings.HasPrefix(typeName, "map["):
+		if keyEnd := strings.Index(typeName, "]"); keyEnd != -1 {
+			key, err := p.resolveType(typeName[4:keyEnd])
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := p.resolveType(typeName[keyEnd+1:])
+			if err != nil {
+				return nil, err
+			}
+			return reflect.MapOf(key, value), nil
+		}
+	}
+
+	if p.typeParser == nil {
+		return nil, fmt.Errorf("unknown type %v, use velty.TypeParser option to resolve custom types", typeName)
+	}
+
+	rType, err := p.typeParser(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	if rType == nil {
+		return nil, fmt.Errorf("unknown type %v", typeName)
+	}
+	return rType, nil
+}
@@ -0,0 +1,145 @@
+package velty
+
+import (
+	"fmt"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+	"github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
+	"github.com/viant/velty/parser"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+const parseKeyPrefix = "#parse:"
+
+type includer struct {
+	x        *op.Operand
+	loader   Loader
+	location *ast.Location
+}
+
+func (i *includer) compute(state *est.State) unsafe.Pointer {
+	name := *(*string)(i.x.Exec(state))
+	content, err := i.loader.Load(name)
+	if err != nil {
+		state.AddError(i.location.WithError(fmt.Errorf("failed to include %v: %w", name, err)))
+		return est.EmptyStringPtr
+	}
+
+	state.Buffer.AppendStringWithoutEscaping(string(content))
+	return est.EmptyStringPtr
+}
+
+func (p *Planner) compileParse(actual *stmt.Parse) (est.New, error) {
+	if name, ok := literalName(actual.X); ok {
+		return p.inlineParse(name)
+	}
+
+	if p.loader == nil {
+		return nil, fmt.Errorf("failed to compile #parse, Loader option was not specified")
+	}
+
+	p.markLoopsUsed()
+	*p.Control |= est.HasStop
+	x, err := p.compileExpr(actual.X)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEvaluator(x, p.cache, p, p.loader)
+}
+
+//inlineParse compiles template with the literal name within the current planner, so that
+//variables and macros defined by the template are visible after #parse
+func (p *Planner) inlineParse(name string) (est.New, error) {
+	if err := p.checkParseCycle(name); err != nil {
+		return nil, err
+	}
+
+	template, err := p.load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := parser.ParseTemplate(name, template, p.spaceGobbling)
+	if err != nil {
+		return nil, err
+	}
+
+	source, sections := p.source, p.sections
+	p.source = &ast.Source{Name: name, Input: template}
+	p.parsing = append(p.parsing, name)
+	defer func() {
+		p.parsing = p.parsing[:len(p.parsing)-1]
+		p.source, p.sections = source, sections
+	}()
+
+	if block, err = p.extend(block); err != nil {
+		return nil, err
+	}
+	return p.compileBlock(block)
+}
+
+func (p *Planner) compileInclude(actual *stmt.Include) (est.New, error) {
+	if name, ok := literalName(actual.X); ok {
+		content, err := p.load(name)
+		if err != nil {
+			return nil, err
+		}
+		return p.compileAppend(stmt.NewAppend(string(content)))
+	}
+
+	if p.loader == nil {
+		return nil, fmt.Errorf("failed to compile #include, Loader option was not specified")
+	}
+
+	x, err := p.compileExpr(actual.X)
+	if err != nil {
+		return nil, err
+	}
+
+	location := p.location("")
+	return func(control est.Control) (est.Compute, error) {
+		operand, err := x.Operand(control)
+		if err != nil {
+			return nil, err
+		}
+		return (&includer{x: operand, loader: p.loader, location: location}).compute, nil
+	}, nil
+}
+
+func (p *Planner) load(name string) ([]byte, error) {
+	if p.loader == nil {
+		return nil, fmt.Errorf("failed to load %v, Loader option was not specified", name)
+	}
+
+	content, err := p.loader.Load(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %v: %w", name, err)
+	}
+	return content, nil
+}
+
+func (p *Planner) checkParseCycle(name string) error {
+	for _, parsed := range p.parsing {
+		if parsed == name {
+			return parseCycleError(p.parsing, name)
+		}
+	}
+	return nil
+}
+
+func parseCycleError(parsing []string, name string) error {
+	return fmt.Errorf("detected #parse cycle: %v -> %v", strings.Join(parsing, " -> "), name)
+}
+
+func literalName(expression ast.Expression) (string, bool) {
+	literal, ok := expression.(*expr.Literal)
+	if !ok || literal.RType.Kind() != reflect.String {
+		return "", false
+	}
+	return literal.Value, true
+}
@@ -7,18 +7,34 @@ import (
 )
 
 type Builder struct {
-	buffer []ast2.StatementContainer
-	block  stmt.Block
+	buffer  []ast2.StatementContainer
+	block   stmt.Block
+	gobbler *gobbler
 }
 
-func NewBuilder() *Builder {
-	return &Builder{}
+func NewBuilder(options ...Option) *Builder {
+	builder := &Builder{}
+	for _, option := range options {
+		switch actual := option.(type) {
+		case SpaceGobbling:
+			builder.gobbler = newGobbler(actual)
+		}
+	}
+	return builder
 }
 
 func (s *Builder) PushStatement(matchToken int, statement ast2.Statement) error {
+	if s.gobbler != nil {
+		s.gobble(matchToken, statement)
+	}
+
 	switch matchToken {
 	case elseIfToken, elseToken:
 		lastNode := s.Last()
+		if forEach, ok := lastNode.(*stmt.ForEach); ok && matchToken == elseToken {
+			return forEach.AddElse()
+		}
+
 		if err := addIfExpression(lastNode, statement); err != nil {
 			return err
 		}
@@ -95,3 +111,57 @@ func (s *Builder) appendStatement(statement ast2.Statement) {
 
 	s.block.AddStatement(statement)
 }
+
+//gobble applies space gobbling to the statement
+func (s *Builder) gobble(matchToken int, statement ast2.Statement) {
+	switch actual := statement.(type) {
+	case *stmt.Append:
+		s.gobbler.text(actual)
+		return
+	case *stmt.Evaluate, *stmt.Parse, *stmt.Include:
+		s.gobbler.reference()
+		return
+	}
+
+	switch matchToken {
+	case macroCallToken:
+		if _, ok := statement.(ast2.StatementContainer); !ok {
+			s.gobbler.reference()
+			return
+		}
+		s.gobbler.directive(matchToken, true)
+	case elseIfToken, elseToken, endToken:
+		s.gobbler.directive(matchToken, false)
+	default:
+		_, isBlock := statement.(ast2.StatementContainer)
+		s.gobbler.directive(matchToken, isBlock)
+	}
+}
+
+//PushSelector appends selector i.e. $foo
+func (s *Builder) PushSelector(statement ast2.Statement) {
+	if s.gobbler != nil {
+		s.gobbler.reference()
+	}
+	s.appendStatement(statement)
+}
+
+//PushComment registers a comment, line comment consumes the line end
+func (s *Builder) PushComment(isLine bool) {
+	if s.gobbler == nil {
+		return
+	}
+
+	if isLine {
+		s.gobbler.lineComment()
+		return
+	}
+	s.gobbler.directive(commentToken, false)
+}
+
+//Finish completes space gobbling of the last template line
+func (s *Builder) Finish() {
+	if s.gobbler != nil {
+		s.gobbler.finish()
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"github.com/viant/parsly"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+	"strings"
+)
+
+//matchSquareBrackets matches either range i.e. [1..$count] or list literal i.e. [1, 2, $x]
+func matchSquareBrackets(text string) (ast.Expression, error) {
+	content := text[1 : len(text)-1]
+	if isRange(content) {
+		return matchRange(parsly.NewCursor("", []byte(content), 0))
+	}
+	return matchList(parsly.NewCursor("", []byte(content), 0))
+}
+
+func isRange(content string) bool {
+	return strings.Contains(content, "..") && !strings.ContainsAny(content, `,"'`)
+}
+
+func matchList(cursor *parsly.Cursor) (*expr.List, error) {
+	result := &expr.List{}
+	for hasElement(cursor) {
+		_, element, err := matchOperand(extractArgument(cursor), dataTypeMatchers...)
+		if err != nil {
+			return nil, err
+		}
+		result.Elements = append(result.Elements, element)
+	}
+	return result, nil
+}
+
+func matchMap(cursor *parsly.Cursor) (*expr.Map, error) {
+	result := &expr.Map{}
+	for hasElement(cursor) {
+		entryCursor := extractArgument(cursor)
+		_, key, err := matchOperand(entryCursor, dataTypeMatchers...)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched := entryCursor.MatchAfterOptional(WhiteSpace, Colon); matched.Code != colonToken {
+			return nil, entryCursor.NewError(Colon)
+		}
+
+		_, value, err := matchOperand(entryCursor, dataTypeMatchers...)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Keys = append(result.Keys, key)
+		result.Values = append(result.Values, value)
+	}
+	return result, nil
+}
+
+func hasElement(cursor *parsly.Cursor) bool {
+	cursor.MatchOne(WhiteSpace)
+	return cursor.Pos < cursor.InputSize
+}
@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"github.com/viant/velty/ast/stmt"
+	"strings"
+)
+
+//SpaceGobbling represents Velocity 2 whitespace gobbling mode around directives
+type SpaceGobbling int
+
+const (
+	//GobbleNone keeps all whitespace as it was defined in the template
+	GobbleNone SpaceGobbling = iota
+	//GobbleBC gobbles new line directly following a directive, and indentation of the #set lines (Velocity 1.x compatible)
+	GobbleBC
+	//GobbleLines gobbles indentation and new line of the lines holding only directives or comments
+	GobbleLines
+	//GobbleStructured gobbles like GobbleLines, and additionally removes block directive extra indentation from its body
+	GobbleStructured
+)
+
+//Option represents parser option
+type Option interface{}
+
+type (
+	//gobbler removes whitespace around directives while the template is being built
+	gobbler struct {
+		mode        SpaceGobbling
+		lineClean   bool           //only whitespace, directives or comments since the last new line
+		atLineStart bool           //next text starts a new line
+		indent      *stmt.Append   //append holding current line indentation
+		indentStart int            //current line indentation offset in the indent append
+		pending     []int          //directives waiting for their line end
+		trailing    []*stmt.Append //whitespaces following pending directives
+		blocks      []*indentBlock
+	}
+
+	//indentBlock represents block directive indentation in the structured mode
+	indentBlock struct {
+		indent int //directive line indentation
+		extra  int //body indentation relative to the directive, -1 if not known yet
+	}
+)
+
+func newGobbler(mode SpaceGobbling) *gobbler {
+	if mode == GobbleNone {
+		return nil
+	}
+	return &gobbler{mode: mode, lineClean: true, atLineStart: true}
+}
+
+//directive registers directive, isBlock is true for the directives terminated with #end
+func (g *gobbler) directive(token int, isBlock bool) {
+	if g.mode == GobbleBC || g.lineClean {
+		g.pending = append(g.pending, token)
+	}
+
+	if g.mode != GobbleStructured {
+		return
+	}
+
+	switch {
+	case token == endToken:
+		if len(g.blocks) > 0 {
+			g.blocks = g.blocks[:len(g.blocks)-1]
+		}
+	case isBlock:
+		block := &indentBlock{}
+		if g.lineClean {
+			block.indent = g.indentLen()
+			block.extra = -1
+		}
+		g.blocks = append(g.blocks, block)
+	}
+}
+
+//lineComment registers ## comment, which consumes the line end
+func (g *gobbler) lineComment() {
+	if g.mode != GobbleBC && g.lineClean {
+		g.gobble()
+	}
+	g.newLine()
+}
+
+//reference registers content rendered in the current line, i.e. $foo or macro call
+func (g *gobbler) reference() {
+	g.pending = nil
+	g.trailing = nil
+	g.lineClean = false
+	g.atLineStart = false
+}
+
+//text gobbles whitespace of the text statement
+func (g *gobbler) text(appendStmt *stmt.Append) {
+	text := appendStmt.Append
+	isTrailing := false
+	if len(g.pending) > 0 {
+		text, isTrailing = g.resolve(text)
+	}
+
+	if g.mode == GobbleStructured {
+		text = g.strip(text)
+	}
+
+	appendStmt.Append = text
+	if isTrailing {
+		g.trailing = append(g.trailing, appendStmt)
+		return
+	}
+	g.track(appendStmt)
+}
+
+//finish gobbles directives of the last template line
+func (g *gobbler) finish() {
+	if len(g.pending) > 0 && g.mode != GobbleBC && g.lineClean {
+		g.gobble()
+	}
+}
+
+//resolve decides if pending directives line is gobbled, returns true if text is whitespace following them
+func (g *gobbler) resolve(text string) (string, bool) {
+	lineEnd := strings.IndexByte(text, '\n')
+	if g.mode == GobbleBC {
+		if strings.HasPrefix(text, "\n") || strings.HasPrefix(text, "\r\n") {
+			if g.lineClean && g.hasPending(setToken) {
+				g.trimIndent()
+			}
+			g.newLine()
+			return text[lineEnd+1:], false
+		}
+		g.pending = nil
+		return text, false
+	}
+
+	rest := text
+	if lineEnd != -1 {
+		rest = text[:lineEnd]
+	}
+
+	switch {
+	case !isBlank(rest):
+		g.pending = nil
+		g.trailing = nil
+		return text, false
+	case lineEnd == -1:
+		return text, true
+	}
+
+	g.gobble()
+	g.newLine()
+	return text[lineEnd+1:], false
+}
+
+func (g *gobbler) hasPending(token int) bool {
+	for _, candidate := range g.pending {
+		if candidate == token {
+			return true
+		}
+	}
+	return false
+}
+
+//gobble removes pending directives line indentation and trailing whitespaces
+func (g *gobbler) gobble() {
+	g.trimIndent()
+	for _, trailing := range g.trailing {
+		trailing.Append = ""
+	}
+	g.pending = nil
+	g.trailing = nil
+}
+
+func (g *gobbler) trimIndent() {
+	if g.indent != nil {
+		g.indent.Append = g.indent.Append[:g.indentStart]
+		g.indent = nil
+	}
+}
+
+func (g *gobbler) indentLen() int {
+	if g.indent == nil {
+		return 0
+	}
+	return len(g.indent.Append) - g.indentStart
+}
+
+func (g *gobbler) newLine() {
+	g.pending = nil
+	g.trailing = nil
+	g.indent = nil
+	g.lineClean = true
+	g.atLineStart = true
+}
+
+//track updates current line state with the text
+func (g *gobbler) track(appendStmt *stmt.Append) {
+	text := appendStmt.Append
+	if text == "" {
+		return
+	}
+
+	if index := strings.LastIndexByte(text, '\n'); index != -1 {
+		g.indent, g.indentStart = appendStmt, index+1
+		g.lineClean = isBlank(text[index+1:])
+		g.atLineStart = index == len(text)-1
+		return
+	}
+
+	if g.atLineStart {
+		g.indent, g.indentStart = appendStmt, 0
+	}
+	g.lineClean = g.lineClean && isBlank(text)
+	g.atLineStart = false
+}
+
+//strip removes enclosing block directives extra indentation from each line of the text
+func (g *gobbler) strip(text string) string {
+	if len(g.blocks) == 0 {
+		return text
+	}
+
+	var result strings.Builder
+	lineStart := g.atLineStart
+	for len(text) > 0 {
+		line := text
+		if index := strings.IndexByte(text, '\n'); index != -1 {
+			line = text[:index+1]
+		}
+		text = text[len(line):]
+
+		if lineStart {
+			line = g.stripLine(line)
+		}
+		result.WriteString(line)
+		lineStart = true
+	}
+	return result.String()
+}
+
+func (g *gobbler) stripLine(line string) string {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	isEmpty := strings.TrimRight(line[indent:], "\r\n") == "" && strings.HasSuffix(line, "\n")
+	total := 0
+	for _, block := range g.blocks {
+		if block.extra == -1 {
+			if isEmpty {
+				break
+			}
+
+			block.extra = indent - total - block.indent
+			if block.extra < 0 {
+				block.extra = 0
+			}
+		}
+		total += block.extra
+	}
+
+	if total > indent {
+		total = indent
+	}
+	return line[total:]
+}
+
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"github.com/viant/parsly"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+	"strings"
+)
+
+//matchInterpolation matches double-quoted string content, references are concatenated with the surrounding text
+//i.e. "Hello $name, you have ${count} items" is parsed as "Hello " + $name + ", you have " + $count + " items"
+func matchInterpolation(value string) ast.Expression {
+	var parts []ast.Expression
+	var text strings.Builder
+	input := []byte(value)
+	for i := 0; i < len(input); i++ {
+		if input[i] != '$' {
+			text.WriteByte(input[i])
+			continue
+		}
+
+		cursor := parsly.NewCursor("", input, 0)
+		cursor.Pos = i + 1
+		selector, err := MatchSelector(cursor)
+		if err != nil {
+			text.WriteByte(input[i])
+			continue
+		}
+
+		if text.Len() > 0 {
+			parts = append(parts, expr.StringLiteral(text.String()))
+			text.Reset()
+		}
+		parts = append(parts, selector)
+		i = cursor.Pos - 1
+	}
+
+	if text.Len() > 0 || len(parts) == 0 {
+		parts = append(parts, expr.StringLiteral(text.String()))
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	if _, ok := parts[0].(*expr.Literal); !ok {
+		parts = append([]ast.Expression{expr.StringLiteral("")}, parts...)
+	}
+
+	result := parts[0]
+	for _, part := range parts[1:] {
+		result = expr.BinaryExpression(result, ast.ADD, part)
+	}
+	return result
+}
+
+//matchRawString returns single-quoted string content, only escaped quote is unescaped
+func matchRawString(value string) *expr.Literal {
+	return expr.StringLiteral(strings.ReplaceAll(value[1:len(value)-1], `\'`, `'`))
+}
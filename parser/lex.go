@@ -23,7 +23,19 @@ const (
 	forToken
 	appendToken
 	evaluateToken
+	macroToken
+	macroCallToken
+	parseToken
+	includeToken
+	paramToken
+	varToken
+	extendsToken
+	blockToken
+	breakToken
+	continueToken
+	stopToken
 	endToken
+	commentToken
 
 	inToken
 
@@ -36,6 +48,7 @@ const (
 	expressionEndToken
 
 	stringToken
+	rawStringToken
 	booleanToken
 	numberToken
 
@@ -61,6 +74,19 @@ const (
 	mulEqualToken
 	quoToken
 	quoEqualToken
+	modToken
+
+	eqWordToken
+	neWordToken
+	ltWordToken
+	gtWordToken
+	leWordToken
+	geWordToken
+	andWordToken
+	orWordToken
+	notWordToken
+
+	questionToken
 
 	decrementToken
 	incrementToken
@@ -68,8 +94,13 @@ const (
 	binaryExpressionStartToken
 
 	comaToken
+	comaSeparatorToken
+	colonToken
+	atToken
 	newLineToken
 	dotToken
+	safeDotToken
+	pipeToken
 	stringFinishToken
 	quoteToken
 	argumentToken
@@ -92,6 +123,16 @@ var ForEach = parsly.NewToken(forEachToken, "ForEach", matcher.NewFragment("fore
 var For = parsly.NewToken(forToken, "For", matcher.NewFragment("for"))
 var In = parsly.NewToken(inToken, "In", matcher.NewFragment("in"))
 var Evaluate = parsly.NewToken(evaluateToken, "Evaluate", matcher.NewFragment("evaluate"))
+var Macro = parsly.NewToken(macroToken, "Macro", matcher.NewFragment("macro"))
+var ParseDirective = parsly.NewToken(parseToken, "Parse", matcher.NewFragment("parse"))
+var Include = parsly.NewToken(includeToken, "Include", matcher.NewFragment("include"))
+var Param = parsly.NewToken(paramToken, "Param", matcher.NewFragment("param"))
+var Var = parsly.NewToken(varToken, "Var", matcher.NewFragment("var"))
+var Extends = parsly.NewToken(extendsToken, "Extends", matcher.NewFragment("extends"))
+var BlockDirective = parsly.NewToken(blockToken, "Block", matcher.NewFragment("block"))
+var Break = parsly.NewToken(breakToken, "Break", matcher3.NewKeyword("break"))
+var Continue = parsly.NewToken(continueToken, "Continue", matcher3.NewKeyword("continue"))
+var Stop = parsly.NewToken(stopToken, "Stop", matcher3.NewKeyword("stop"))
 var End = parsly.NewToken(endToken, "End", matcher.NewFragment("end"))
 
 var Parentheses = parsly.NewToken(parenthesesToken, "Parentheses", matcher.NewBlock('(', ')', '\\'))
@@ -117,6 +158,7 @@ var And = parsly.NewToken(andToken, "And", matcher.NewFragment("&&"))
 var Or = parsly.NewToken(orToken, "Or", matcher.NewFragment("||"))
 
 var String = parsly.NewToken(stringToken, "String", matcher3.NewStringMatcher('"'))
+var RawString = parsly.NewToken(rawStringToken, "Raw string", matcher3.NewStringMatcher('\''))
 var Boolean = parsly.NewToken(booleanToken, "Boolean", matcher.NewFragments([]byte("true"), []byte("false")))
 var Number = parsly.NewToken(numberToken, "Number", matcher.NewNumber())
 
@@ -129,12 +171,31 @@ var MultiplyEqual = parsly.NewToken(mulEqualToken, "Multiply equal", matcher.New
 var Quo = parsly.NewToken(quoToken, "Quo", matcher.NewByte('/'))
 var QuoEqual = parsly.NewToken(quoEqualToken, "Quo equal", matcher.NewBytes([]byte("/=")))
 
+var Mod = parsly.NewToken(modToken, "Mod", matcher.NewByte('%'))
+
+var EqWord = parsly.NewToken(eqWordToken, "eq", matcher3.NewKeyword("eq"))
+var NeWord = parsly.NewToken(neWordToken, "ne", matcher3.NewKeyword("ne"))
+var LtWord = parsly.NewToken(ltWordToken, "lt", matcher3.NewKeyword("lt"))
+var GtWord = parsly.NewToken(gtWordToken, "gt", matcher3.NewKeyword("gt"))
+var LeWord = parsly.NewToken(leWordToken, "le", matcher3.NewKeyword("le"))
+var GeWord = parsly.NewToken(geWordToken, "ge", matcher3.NewKeyword("ge"))
+var AndWord = parsly.NewToken(andWordToken, "and", matcher3.NewKeyword("and"))
+var OrWord = parsly.NewToken(orWordToken, "or", matcher3.NewKeyword("or"))
+var NotWord = parsly.NewToken(notWordToken, "not", matcher3.NewKeyword("not"))
+
+var Question = parsly.NewToken(questionToken, "Question", matcher.NewByte('?'))
+
 var Decrement = parsly.NewToken(decrementToken, "Decrement", matcher.NewBytes([]byte("--")))
 var Increment = parsly.NewToken(incrementToken, "Increment", matcher.NewBytes([]byte("++")))
 
 var ComaTerminator = parsly.NewToken(comaToken, "Coma", matcher.NewTerminator(',', true))
+var ComaSeparator = parsly.NewToken(comaSeparatorToken, "Coma separator", matcher.NewByte(','))
+var Colon = parsly.NewToken(colonToken, "Colon", matcher.NewByte(':'))
+var At = parsly.NewToken(atToken, "At", matcher.NewByte('@'))
 var NewLine = parsly.NewToken(newLineToken, "New line", matcher3.NewNewLine())
 var Dot = parsly.NewToken(dotToken, "Dot", matcher.NewByte('.'))
+var SafeDot = parsly.NewToken(safeDotToken, "Safe dot", matcher.NewFragment("?."))
+var Pipe = parsly.NewToken(pipeToken, "Pipe", matcher.NewByte('|'))
 
 var Quote = parsly.NewToken(quoteToken, "Quote", matcher.NewByte('"'))
 var StringFinish = parsly.NewToken(stringFinishToken, "Quote terminated", matcher.NewTerminator('"', true))
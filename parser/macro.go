@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"github.com/viant/parsly"
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/stmt"
+)
+
+func matchMacro(cursor *parsly.Cursor) (*stmt.Macro, error) {
+	candidates := []*parsly.Token{Selector}
+	matched := cursor.MatchAfterOptional(WhiteSpace, candidates...)
+	if matched.Code != selectorToken {
+		return nil, cursor.NewError(candidates...)
+	}
+
+	macro := &stmt.Macro{Name: matched.Text(cursor)}
+	for {
+		matched = cursor.MatchAfterOptional(WhiteSpace, ComaSeparator)
+		if matched.Code == parsly.EOF {
+			break
+		}
+
+		param, err := matchVariable(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		macro.Params = append(macro.Params, param)
+	}
+
+	return macro, nil
+}
+
+func matchMacroCall(cursor *parsly.Cursor, start int) (ast.Statement, int, error) {
+	isBlock := cursor.MatchOne(At).Code == atToken
+
+	candidates := []*parsly.Token{Selector}
+	matched := cursor.MatchAny(candidates...)
+	if matched.Code != selectorToken {
+		return nil, 0, cursor.NewError(candidates...)
+	}
+
+	name := matched.Text(cursor)
+	argsCursor, err := matchExpressionBlock(cursor)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args, err := matchMacroArgs(argsCursor)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	call := stmt.MacroCall{
+		Name: name,
+		Args: args,
+		Raw:  "#" + string(cursor.Input[start:cursor.Pos]),
+	}
+
+	if isBlock {
+		call.Body = &stmt.Block{}
+		return &stmt.BlockMacroCall{MacroCall: call}, macroCallToken, nil
+	}
+
+	return &call, macroCallToken, nil
+}
+
+func matchMacroArgs(cursor *parsly.Cursor) ([]ast.Expression, error) {
+	var args []ast.Expression
+	for {
+		matched := cursor.MatchAfterOptional(WhiteSpace, ComaSeparator)
+		if matched.Code == parsly.EOF {
+			return args, nil
+		}
+
+		_, arg, err := matchOperand(cursor, String, Boolean, Number)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+}
@@ -9,20 +9,29 @@ type argument struct {
 
 func (a *argument) Match(cursor *parsly.Cursor) (matched int) {
 	depth := 0
-	inQuote := false
+	var quote byte
 	for i := cursor.Pos; i < cursor.InputSize; i++ {
 		matched++
-		switch cursor.Input[i] {
-		case '(':
+		inQuote := quote != 0
+		switch value := cursor.Input[i]; value {
+		case '(', '[', '{':
 			if !inQuote {
 				depth++
 			}
-		case ')':
+		case ')', ']', '}':
 			if depth > 0 && !inQuote {
 				depth--
 			}
-		case '"':
-			inQuote = !inQuote
+		case '"', '\'':
+			if i > cursor.Pos && cursor.Input[i-1] == '\\' {
+				continue
+			}
+
+			if !inQuote {
+				quote = value
+			} else if quote == value {
+				quote = 0
+			}
 		case ',':
 			if depth == 0 && !inQuote {
 				return matched
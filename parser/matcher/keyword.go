@@ -0,0 +1,32 @@
+package matcher
+
+import (
+	"bytes"
+	"github.com/viant/parsly"
+)
+
+type keyword struct {
+	value []byte
+}
+
+//Match matches a keyword, which is not followed by an identifier character
+func (k *keyword) Match(cursor *parsly.Cursor) (matched int) {
+	end := cursor.Pos + len(k.value)
+	if end > cursor.InputSize || !bytes.Equal(cursor.Input[cursor.Pos:end], k.value) {
+		return 0
+	}
+
+	if end < cursor.InputSize && isIdentifierChar(cursor.Input[end]) {
+		return 0
+	}
+	return len(k.value)
+}
+
+func isIdentifierChar(b byte) bool {
+	return IsLetter(b) || (b >= '0' && b <= '9') || b == '_'
+}
+
+//NewKeyword creates a keyword matcher, i.e. `and` matches `and $b` but not `android`
+func NewKeyword(value string) *keyword {
+	return &keyword{value: []byte(value)}
+}
@@ -0,0 +1,53 @@
+package matcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/parsly"
+	"testing"
+)
+
+func TestKeyword_Match(t *testing.T) {
+	useCases := []struct {
+		description string
+		keyword     string
+		input       []byte
+		matched     int
+	}{
+		{
+			description: "keyword followed by whitespace",
+			keyword:     "and",
+			input:       []byte("and $b"),
+			matched:     3,
+		},
+		{
+			description: "keyword followed by selector",
+			keyword:     "eq",
+			input:       []byte("eq$b"),
+			matched:     2,
+		},
+		{
+			description: "keyword at the end of input",
+			keyword:     "or",
+			input:       []byte("or"),
+			matched:     2,
+		},
+		{
+			description: "keyword prefix of identifier",
+			keyword:     "and",
+			input:       []byte("android"),
+			matched:     0,
+		},
+		{
+			description: "other word",
+			keyword:     "not",
+			input:       []byte("no"),
+			matched:     0,
+		},
+	}
+
+	for _, useCase := range useCases {
+		aKeyword := NewKeyword(useCase.keyword)
+		matched := aKeyword.Match(parsly.NewCursor("", useCase.input, 0))
+		assert.Equal(t, useCase.matched, matched, useCase.description)
+	}
+}
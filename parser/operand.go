@@ -10,10 +10,23 @@ import (
 var dataTypeMatchers = []*parsly.Token{String, Boolean, Number}
 
 func matchOperand(cursor *parsly.Cursor, candidates ...*parsly.Token) (*parsly.Token, ast2.Expression, error) {
-	matched := cursor.MatchAfterOptional(WhiteSpace, Negation)
-	hasNegation := matched.Code == negationToken
+	matcher, expression, err := matchSingleOperand(cursor, candidates...)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	candidates = append([]*parsly.Token{Quote, SelectorStart, Parentheses}, candidates...)
+	if err = addEquationIfNeeded(cursor, &expression); err != nil {
+		return nil, nil, err
+	}
+
+	return matcher, expression, nil
+}
+
+//matchSingleOperand matches operand without following binary operators
+func matchSingleOperand(cursor *parsly.Cursor, candidates ...*parsly.Token) (*parsly.Token, ast2.Expression, error) {
+	matched := cursor.MatchAfterOptional(WhiteSpace, Negation, NotWord)
+	hasNegation := matched.Code == negationToken || matched.Code == notWordToken
+	candidates = append([]*parsly.Token{Quote, RawString, SelectorStart, Parentheses, SquareBrackets, Brackets}, candidates...)
 
 	matched = cursor.MatchAfterOptional(WhiteSpace, candidates...)
 
@@ -42,6 +55,19 @@ func matchOperand(cursor *parsly.Cursor, candidates ...*parsly.Token) (*parsly.T
 		}
 
 		return token, expr, nil
+	case squareBracketsToken:
+		expression, err = matchSquareBrackets(matched.Text(cursor))
+		if err != nil {
+			return nil, nil, err
+		}
+
+	case bracketsToken:
+		text := matched.Text(cursor)
+		expression, err = matchMap(parsly.NewCursor("", []byte(text[1:len(text)-1]), 0))
+		if err != nil {
+			return nil, nil, err
+		}
+
 	case stringToken:
 		value := matched.Text(cursor)
 		matcher = String
@@ -72,22 +98,12 @@ func matchOperand(cursor *parsly.Cursor, candidates ...*parsly.Token) (*parsly.T
 		}
 
 		value := matched.Text(cursor)
-		if len(value) == 1 { // matched `"`
-			matcher = String
-			expression = aexpr.StringLiteral("")
-		} else {
-			newCursor := parsly.NewCursor("", []byte(value[:len(value)-1]), 0)
-
-			matcher, expression, err = matchOperand(newCursor, candidates...)
-			if err != nil {
-				expression = aexpr.StringLiteral(value[:len(value)-1])
-			} else {
-				if _, ok := expression.(*aexpr.Select); !ok {
-					expression = aexpr.StringLiteral(value[:len(value)-1])
-				}
-			}
-		}
+		matcher = String
+		expression = matchInterpolation(value[:len(value)-1])
 
+	case rawStringToken:
+		matcher = RawString
+		expression = matchRawString(matched.Text(cursor))
 	}
 
 	if hasNegation {
@@ -96,22 +112,23 @@ func matchOperand(cursor *parsly.Cursor, candidates ...*parsly.Token) (*parsly.T
 			X:     expression,
 		}
 	}
-	err = addEquationIfNeeded(cursor, &expression)
-	if err != nil {
-		return nil, nil, err
-	}
 
 	return matcher, expression, nil
 }
 
+//addEquationIfNeeded matches binary operators chain following the expression, and optionally ternary expression
 func addEquationIfNeeded(cursor *parsly.Cursor, expression *ast2.Expression) error {
+	operands := []ast2.Expression{*expression}
+	var tokens []ast2.Token
 	for {
-		candidates := []*parsly.Token{Add, Sub, Multiply, Quo, NotEqual, Negation, Equal, And, Or, GreaterEqual, Greater, LessEqual, Less, Assign}
+		candidates := []*parsly.Token{Add, Sub, Multiply, Quo, Mod, NotEqual, Negation, Equal, And, Or, GreaterEqual, Greater, LessEqual, Less, Assign,
+			EqWord, NeWord, LtWord, GtWord, LeWord, GeWord, AndWord, OrWord}
 		matched := cursor.MatchAfterOptional(WhiteSpace, candidates...)
 
 		switch matched.Code {
 		case parsly.EOF, binaryExpressionStartToken, parsly.Invalid:
-			return nil
+			*expression = binaryExpression(operands, tokens)
+			return addTernaryIfNeeded(cursor, expression)
 		}
 
 		token := matchToken(matched)
@@ -119,55 +136,75 @@ func addEquationIfNeeded(cursor *parsly.Cursor, expression *ast2.Expression) err
 			return fmt.Errorf("assignment in expression is not allowed")
 		}
 
-		eprCursor, err := matchExpressionBlock(cursor)
-
-		var rightExpression ast2.Expression
-		if err == nil {
-			rightExpression, err = matchEquationExpression(eprCursor)
-			rightExpression = &aexpr.Parentheses{P: rightExpression}
-		} else {
-			_, rightExpression, err = matchOperand(cursor, dataTypeMatchers...)
-		}
-
+		_, operand, err := matchSingleOperand(cursor, dataTypeMatchers...)
 		if err != nil {
 			return err
 		}
-		hasPrecedence := isPrecedenceToken(token)
 
-		if hasPrecedence {
-			y, ok := rightExpression.(*aexpr.Binary)
-			if ok && !isPrecedenceToken(y.Token) {
-				expression = adjustPrecedence(expression, token, y)
-				continue
-			}
+		operands = append(operands, operand)
+		tokens = append(tokens, token)
+	}
+}
 
-		}
+func addTernaryIfNeeded(cursor *parsly.Cursor, expression *ast2.Expression) error {
+	if matched := cursor.MatchAfterOptional(WhiteSpace, Question); matched.Code != questionToken {
+		return nil
+	}
 
-		*expression = &aexpr.Binary{
-			X:     *expression,
-			Token: token,
-			Y:     rightExpression,
-		}
+	_, x, err := matchOperand(cursor, dataTypeMatchers...)
+	if err != nil {
+		return err
+	}
+
+	if matched := cursor.MatchAfterOptional(WhiteSpace, Colon); matched.Code != colonToken {
+		return cursor.NewError(Colon)
+	}
+
+	_, y, err := matchOperand(cursor, dataTypeMatchers...)
+	if err != nil {
+		return err
 	}
+
+	*expression = &aexpr.Ternary{Cond: *expression, X: x, Y: y}
+	return nil
 }
 
-func adjustPrecedence(expression *ast2.Expression, token ast2.Token, y *aexpr.Binary) *ast2.Expression {
-	p := &aexpr.Parentheses{}
-	p.P = &aexpr.Binary{
-		X:     *expression,
-		Token: token,
-		Y:     y.X,
+//binaryExpression builds left associative binary expressions tree, respecting operators precedence
+func binaryExpression(operands []ast2.Expression, tokens []ast2.Token) ast2.Expression {
+	output := []ast2.Expression{operands[0]}
+	var pending []ast2.Token
+	reduce := func() {
+		x, y := output[len(output)-2], output[len(output)-1]
+		output = append(output[:len(output)-2], &aexpr.Binary{X: x, Token: pending[len(pending)-1], Y: y})
+		pending = pending[:len(pending)-1]
 	}
 
-	*expression = &aexpr.Binary{
-		X:     p,
-		Token: y.Token,
-		Y:     y.Y,
+	for i, token := range tokens {
+		for len(pending) > 0 && precedence(pending[len(pending)-1]) >= precedence(token) {
+			reduce()
+		}
+		pending = append(pending, token)
+		output = append(output, operands[i+1])
 	}
-	return expression
+
+	for len(pending) > 0 {
+		reduce()
+	}
+	return output[0]
 }
 
-func isPrecedenceToken(token ast2.Token) bool {
-	hasPrecedence := token == ast2.MUL || token == ast2.QUO
-	return hasPrecedence
+func precedence(token ast2.Token) int {
+	switch token {
+	case ast2.MUL, ast2.QUO, ast2.MOD:
+		return 5
+	case ast2.ADD, ast2.SUB:
+		return 4
+	case ast2.GTR, ast2.GTE, ast2.LSS, ast2.LEQ:
+		return 3
+	case ast2.EQ, ast2.NEQ:
+		return 2
+	case ast2.AND:
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/parsly"
+	"github.com/viant/velty/ast/stmt"
+	"strings"
+)
+
+//matchParam matches template input declaration content i.e. `$limit int`, `$user "github.com/acme/model.User"`
+func matchParam(cursor *parsly.Cursor) (*stmt.Param, error) {
+	variable, err := matchVariable(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if variable.X != nil {
+		return nil, fmt.Errorf("expected plain variable name, but had %v", string(cursor.Input))
+	}
+
+	typeName := strings.TrimSpace(string(cursor.Input[cursor.Pos:]))
+	if size := len(typeName); size > 1 && (typeName[0] == '"' || typeName[0] == '\'') && typeName[size-1] == typeName[0] {
+		typeName = typeName[1 : size-1]
+	}
+
+	if typeName == "" {
+		return nil, fmt.Errorf("missing $%v type", variable.ID)
+	}
+	return &stmt.Param{Name: variable.ID, Type: typeName}, nil
+}
@@ -1,20 +1,29 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/viant/parsly"
 	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/expr"
 	"github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/parser/matcher"
 	"strings"
 )
 
+//Parse parses template into the statements block
 func Parse(input []byte) (*stmt.Block, error) {
+	return ParseTemplate("", input)
+}
+
+//ParseTemplate parses template with given name, errors are returned as *ast.Error with the template name and position
+func ParseTemplate(name string, input []byte, options ...Option) (*stmt.Block, error) {
 	if len(input) == 0 {
 		return &stmt.Block{}, nil
 	}
 
-	builder := NewBuilder()
+	source := &ast.Source{Name: name, Input: input}
+	builder := NewBuilder(options...)
 	var tokenMatch *parsly.TokenMatch
 	cursor := parsly.NewCursor("", input, 0)
 outer:
@@ -24,19 +33,29 @@ outer:
 
 		if tokenMatch.Code == parsly.EOF || cursor.Pos >= len(input) {
 			if err := builder.PushStatement(appendToken, stmt.NewAppend(text)); err != nil {
-				return nil, cursorErr(cursor, err)
+				return nil, cursorErr(source, cursor.Pos, err)
 			}
 			break
 		}
 
-		if cursor.Input[cursor.Pos] == '#' {
-			cursor.MatchOne(NewLine)
-			continue
-		}
-
 		err := appendStatementIfNeeded(text, builder)
 		if err != nil {
-			return nil, cursorErr(cursor, err)
+			return nil, cursorErr(source, cursor.Pos, err)
+		}
+
+		if cursor.Input[cursor.Pos-1] == '#' {
+			switch cursor.Input[cursor.Pos] {
+			case '#':
+				cursor.MatchOne(NewLine)
+				builder.PushComment(true)
+				continue
+			case '*':
+				if err = skipBlockComment(cursor); err != nil {
+					return nil, cursorErr(source, cursor.Pos-1, err)
+				}
+				builder.PushComment(false)
+				continue
+			}
 		}
 
 		lastPosition := cursor.Pos - 1
@@ -46,11 +65,12 @@ outer:
 			if err != nil {
 				rawValue := cursor.Input[lastPosition:cursor.Pos]
 				if errr := builder.PushStatement(appendToken, stmt.NewAppend(string(rawValue))); errr != nil {
-					return nil, cursorErr(cursor, errr)
+					return nil, cursorErr(source, lastPosition, errr)
 				}
 				continue outer
 			}
-			builder.appendStatement(statement)
+			locate(statement, lastPosition, cursor.Pos)
+			builder.PushSelector(statement)
 
 		case '#':
 			appendStmt, ok := checkIfEscaped(cursor)
@@ -65,24 +85,40 @@ outer:
 			if err != nil {
 				rawValue := cursor.Input[lastPosition:cursor.Pos]
 				if errr := builder.PushStatement(appendToken, stmt.NewAppend(string(rawValue))); errr != nil {
-					return nil, cursorErr(cursor, errr)
+					return nil, cursorErr(source, lastPosition, errr)
 				}
 				continue outer
 			}
 
+			locate(statement, lastPosition, cursor.Pos)
 			if err = builder.PushStatement(match, statement); err != nil {
-				return nil, cursorErr(cursor, err)
+				return nil, source.NewError(&ast.Pos{Offset: lastPosition, Length: cursor.Pos - lastPosition}, err)
 			}
 		}
 	}
 
+	builder.Finish()
 	if builder.BufferSize() != 0 {
-		return nil, fmt.Errorf("unterminated statements on the stack: %v", builder.buffer)
+		var pos *ast.Pos
+		if locatable, ok := builder.Last().(ast.Locatable); ok {
+			pos = locatable.Position()
+		}
+		return nil, source.NewError(pos, fmt.Errorf("unterminated statements on the stack: %v", builder.buffer))
 	}
 
 	return builder.Block(), nil
 }
 
+//skipBlockComment skips #* ... *# comment, cursor is expected to be positioned after the opening #
+func skipBlockComment(cursor *parsly.Cursor) error {
+	end := bytes.Index(cursor.Input[cursor.Pos+1:], []byte("*#"))
+	if end == -1 {
+		return fmt.Errorf("unterminated block comment")
+	}
+	cursor.Pos += end + 3
+	return nil
+}
+
 func checkIfEscaped(cursor *parsly.Cursor) (*stmt.Append, bool) {
 	lastCursorPos := cursor.Pos
 	matched := cursor.MatchOne(SquareBrackets)
@@ -100,8 +136,21 @@ func checkIfEscaped(cursor *parsly.Cursor) (*stmt.Append, bool) {
 	return nil, false
 }
 
-func cursorErr(cursor *parsly.Cursor, err error) error {
-	return fmt.Errorf("%w, cursor position: %v", err, cursor.Pos)
+func cursorErr(source *ast.Source, offset int, err error) error {
+	return source.NewError(&ast.Pos{Offset: offset}, err)
+}
+
+//locate sets statement position in the template source
+func locate(statement ast.Statement, start, end int) {
+	if locatable, ok := statement.(ast.Locatable); ok {
+		pos := locatable.Position()
+		pos.Offset = start
+		pos.Length = end - start
+	}
+}
+
+func isIdentifierPart(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || matcher.IsLetter(b)
 }
 
 func appendStatementIfNeeded(text string, stack *Builder) error {
@@ -117,6 +166,7 @@ func appendStatementIfNeeded(text string, stack *Builder) error {
 }
 
 func matchStatement(cursor *parsly.Cursor) (ast.Statement, int, error) {
+	start := cursor.Pos
 	matched := cursor.MatchAfterOptional(WhiteSpace, Brackets)
 	if matched.Token.Code == bracketsToken {
 		stmt := matched.Text(cursor)
@@ -124,13 +174,29 @@ func matchStatement(cursor *parsly.Cursor) (ast.Statement, int, error) {
 		return matchStatement(newCursor)
 	}
 
-	candidates := []*parsly.Token{If, ElseIf, Else, Set, ForEach, For, Evaluate, End}
+	candidates := []*parsly.Token{If, ElseIf, Else, Set, ForEach, For, Evaluate, ParseDirective, Include, Param, Var, Extends, BlockDirective, Macro, Break, Continue, Stop, End}
 	expressionMatch := cursor.MatchAfterOptional(WhiteSpace, candidates...)
 	expressionCode := expressionMatch.Code
 
-	switch expressionMatch.Code {
-	case parsly.EOF, parsly.Invalid:
+	switch expressionCode {
+	case parsly.EOF:
 		return nil, 0, cursor.NewError(candidates...)
+	case parsly.Invalid:
+		cursor.Pos = start
+		return matchMacroCall(cursor, start)
+	}
+
+	if cursor.Pos < cursor.InputSize && isIdentifierPart(cursor.Input[cursor.Pos]) {
+		//i.e. #elseText keeps matching #else, #break, #continue and #stop are matched as keywords only
+		keywordEnd := cursor.Pos
+		cursor.Pos = start
+		if macroCall, code, err := matchMacroCall(cursor, start); err == nil {
+			return macroCall, code, nil
+		}
+		cursor.Pos = keywordEnd
+	}
+
+	switch expressionCode {
 	case ifToken, elseIfToken:
 		expressionCursor, err := matchExpressionBlock(cursor)
 		if err != nil {
@@ -202,6 +268,84 @@ func matchStatement(cursor *parsly.Cursor) (ast.Statement, int, error) {
 		}
 
 		return &stmt.Evaluate{X: operand}, expressionCode, nil
+
+	case parseToken, includeToken:
+		loadCursor, err := matchExpressionBlock(cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		_, operand, err := matchOperand(loadCursor, String)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if expressionCode == includeToken {
+			return &stmt.Include{X: operand}, expressionCode, nil
+		}
+		return &stmt.Parse{X: operand}, expressionCode, nil
+
+	case paramToken, varToken:
+		paramCursor, err := matchExpressionBlock(cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		param, err := matchParam(paramCursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		param.Required = expressionCode == paramToken
+		return param, expressionCode, nil
+
+	case extendsToken:
+		extendsCursor, err := matchExpressionBlock(cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		_, operand, err := matchOperand(extendsCursor, String)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &stmt.Extends{X: operand}, expressionCode, nil
+
+	case blockToken:
+		blockCursor, err := matchExpressionBlock(cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		_, operand, err := matchOperand(blockCursor, String)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		literal, ok := operand.(*expr.Literal)
+		if !ok {
+			return nil, 0, fmt.Errorf("expected #block name literal, but had %T", operand)
+		}
+		return &stmt.NamedBlock{Name: literal.Value}, expressionCode, nil
+
+	case macroToken:
+		macroCursor, err := matchExpressionBlock(cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		macroStmt, err := matchMacro(macroCursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return macroStmt, expressionCode, nil
+	case breakToken:
+		return &stmt.Break{}, expressionCode, nil
+	case continueToken:
+		return &stmt.Continue{}, expressionCode, nil
+	case stopToken:
+		return &stmt.Stop{}, expressionCode, nil
 	case endToken:
 		return nil, expressionCode, nil
 	}
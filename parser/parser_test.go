@@ -98,7 +98,7 @@ func TestService_Parse(t *testing.T) {
 		{
 			description: "if statement with brackets ( ) #1",
 			input:       `#if((1==1 && 2==2) && (3 ==3 || 4 == 4))abc#end`,
-			output:      `{ "Stmt": [ { "Condition": { "P": { "X": { "X": { "Value": "1" }, "Token": "==", "Y": { "X": { "Value": "1" }, "Token": "&&", "Y": { "X": { "Value": "2" }, "Token": "==", "Y": { "Value": "2" } } } }, "Token": "&&", "Y": { "P": { "X": { "Value": "3" }, "Token": "==", "Y": { "X": { "Value": "3" }, "Token": "||", "Y": { "X": { "Value": "4" }, "Token": "==", "Y": { "Value": "4" } } } } } } }, "Body": { "Stmt": [ { "Append": "abc" } ] } } ] }`,
+			output:      `{ "Stmt": [ { "Condition": { "P": { "X": { "X": { "X": { "Value": "1" }, "Token": "==", "Y": { "Value": "1" } }, "Token": "&&", "Y": { "X": { "Value": "2" }, "Token": "==", "Y": { "Value": "2" } } }, "Token": "&&", "Y": { "P": { "X": { "X": { "Value": "3" }, "Token": "==", "Y": { "Value": "3" } }, "Token": "||", "Y": { "X": { "Value": "4" }, "Token": "==", "Y": { "Value": "4" } } } } } }, "Body": { "Stmt": [ { "Append": "abc" } ] } } ] }`,
 		},
 		{
 			description: "if statement binary without token and right hand",
@@ -123,7 +123,7 @@ func TestService_Parse(t *testing.T) {
 		{
 			description: "if statement, nested add equation",
 			input:       `#if( 2 == 1+1+1)abc#end`,
-			output:      `{ "Stmt": [ { "Condition": { "X": { "Value": "2" }, "Token": "==", "Y": { "X": { "Value": "1" }, "Token": "+", "Y": { "X": { "Value": "1" }, "Token": "+", "Y": { "Value": "1" } } } }, "Body": { "Stmt": [ { "Append": "abc" } ] } } ] }`,
+			output:      `{ "Stmt": [ { "Condition": { "X": { "Value": "2" }, "Token": "==", "Y": { "X": { "X": { "Value": "1" }, "Token": "+", "Y": { "Value": "1" } }, "Token": "+", "Y": { "Value": "1" } } }, "Body": { "Stmt": [ { "Append": "abc" } ] } } ] }`,
 		},
 		{
 			description: "if statement, sub equation",
@@ -193,7 +193,7 @@ func TestService_Parse(t *testing.T) {
 		{
 			description: "multiple comparisons",
 			input:       `#if( $id == 1 == true == false )#end`,
-			output:      `{ "Stmt": [ { "Condition": { "X": { "ID": "id" }, "Token": "==", "Y": { "X": { "Value": "1" }, "Token": "==", "Y": { "X": { "Value": "true" }, "Token": "==", "Y": { "Value": "false" } } } } } ] }`,
+			output:      `{ "Stmt": [ { "Condition": { "X": { "X": { "X": { "ID": "id" }, "Token": "==", "Y": { "Value": "1" } }, "Token": "==", "Y": { "Value": "true" } }, "Token": "==", "Y": { "Value": "false" } } } ] }`,
 		},
 		{
 			description: "function call",
@@ -237,6 +237,31 @@ func TestService_Parse(t *testing.T) {
 			input:       `#foreach($int in [-10...10]) abc #end`,
 			output:      `{ "Stmt": [ { "Item": { "ID": "int" }, "Set": { "X": { "Value": "-10" }, "Y": { "Value": "10" } }, "Body": { "Stmt": [ { "Append": " abc " } ] } } ] }`,
 		},
+		{
+			description: `inclusive range`,
+			input:       `#foreach($i in [1..$count]) abc #end`,
+			output:      `{ "Stmt": [ { "Item": { "ID": "i" }, "Set": { "X": { "Value": "1" }, "Y": { "ID": "count" }, "Inclusive": true }, "Body": { "Stmt": [ { "Append": " abc " } ] } } ] }`,
+		},
+		{
+			description: `operators precedence`,
+			input:       `#set($x = 1 + 2 * 3 % 4 - 5)`,
+			output:      `{ "Stmt": [ { "X": { "ID": "x" }, "Op": "=", "Y": { "X": { "X": { "Value": "1" }, "Token": "+", "Y": { "X": { "X": { "Value": "2" }, "Token": "*", "Y": { "Value": "3" } }, "Token": "%", "Y": { "Value": "4" } } }, "Token": "-", "Y": { "Value": "5" } } } ] }`,
+		},
+		{
+			description: `word operators`,
+			input:       `#if(not $a and $b eq 1 or $c ge 2)#end`,
+			output:      `{ "Stmt": [ { "Condition": { "X": { "X": { "Token": "!", "X": { "ID": "a" } }, "Token": "&&", "Y": { "X": { "ID": "b" }, "Token": "==", "Y": { "Value": "1" } } }, "Token": "||", "Y": { "X": { "ID": "c" }, "Token": ">=", "Y": { "Value": "2" } } } } ] }`,
+		},
+		{
+			description: `ternary`,
+			input:       `#set($x = $a > 1 ? "a" : $b ? 1 : 2)`,
+			output:      `{ "Stmt": [ { "X": { "ID": "x" }, "Op": "=", "Y": { "Cond": { "X": { "ID": "a" }, "Token": ">", "Y": { "Value": "1" } }, "X": { "Value": "a" }, "Y": { "Cond": { "ID": "b" }, "X": { "Value": "1" }, "Y": { "Value": "2" } } } } ] }`,
+		},
+		{
+			description: `keyword prefixed selector`,
+			input:       `#if($android == $order)#end`,
+			output:      `{ "Stmt": [ { "Condition": { "X": { "ID": "android" }, "Token": "==", "Y": { "ID": "order" } } } ] }`,
+		},
 		{
 			description: `method call`,
 			input:       `$bar.Concat($foo, $var.toUpperCase(), "abcdef")`,
@@ -301,6 +326,101 @@ func TestService_Parse(t *testing.T) {
 			input:       `#set($value = ("Values: " + 1) + (" another one: " + 5.21))$value`,
 			output:      `{ "Stmt": [ { "X": { "ID": "value", "FullName": "" }, "Op": "=", "Y": { "X": { "P": { "X": { "Value": "Values: " }, "Token": "+", "Y": { "Value": "1" } } }, "Token": "+", "Y": { "P": { "X": { "Value": " another one: " }, "Token": "+", "Y": { "Value": "5.21" } } } } }, { "ID": "value", "FullName": "$value" } ] }`,
 		},
+		{
+			description: `quiet reference`,
+			input:       `$!foo.Name $!{bar} $baz`,
+			output:      `{ "Stmt": [ { "ID": "foo", "Quiet": true }, { "Append": " " }, { "ID": "bar", "Quiet": true }, { "Append": " " }, { "ID": "baz", "Quiet": false } ] }`,
+		},
+		{
+			description: `alternate value`,
+			input:       `${name|'anonymous'} ${user.Name | $default}`,
+			output:      `{ "Stmt": [ { "ID": "name", "Default": { "Value": "anonymous" } }, { "Append": " " }, { "ID": "user", "X": { "ID": "Name" }, "Default": { "ID": "default" } } ] }`,
+		},
+		{
+			description: `null-safe navigation`,
+			input:       `$order?.Customer.Name $name?.`,
+			output:      `{ "Stmt": [ { "ID": "order", "NullSafe": false, "X": { "ID": "Customer", "NullSafe": true, "X": { "ID": "Name", "NullSafe": false } } }, { "Append": " " }, { "ID": "name" }, { "Append": "?." } ] }`,
+		},
+		{
+			description: `statement positions`,
+			input:       `abc #set($a = 1) ${b}`,
+			output:      `{ "Stmt": [ { "Append": "abc " }, { "Offset": 4, "Length": 12 }, { "Append": " " }, { "ID": "b", "Offset": 17, "Length": 4 } ] }`,
+		},
+		{
+			description: `parse and include`,
+			input:       `#parse("header.vm")#include($static)`,
+			output:      `{ "Stmt": [ { "X": { "Value": "header.vm" } }, { "X": { "ID": "static" } } ] }`,
+		},
+		{
+			description: `extends and block`,
+			input:       `#extends("layout.vm")#block("content")Hello#end`,
+			output:      `{ "Stmt": [ { "X": { "Value": "layout.vm" } }, { "Name": "content", "Body": { "Stmt": [ { "Append": "Hello" } ] } } ] }`,
+		},
+		{
+			description: `param and var`,
+			input:       `#param($limit int)#var( $user "github.com/acme/model.User" )`,
+			output:      `{ "Stmt": [ { "Name": "limit", "Type": "int", "Required": true }, { "Name": "user", "Type": "github.com/acme/model.User", "Required": false } ] }`,
+		},
+		{
+			description: `macro definition`,
+			input:       `#macro(greet $name, $title)Hello $title $name#end`,
+			output:      `{ "Stmt": [ { "Name": "greet", "Params": [ { "ID": "name" }, { "ID": "title" } ], "Body": { "Stmt": [ { "Append": "Hello " }, { "ID": "title" }, { "Append": " " }, { "ID": "name" } ] } } ] }`,
+		},
+		{
+			description: `macro call`,
+			input:       `#greet($user.Name "Mr")`,
+			output:      `{ "Stmt": [ { "Name": "greet", "Args": [ { "ID": "user", "X": { "ID": "Name" } }, { "Value": "Mr" } ], "Raw": "#greet($user.Name \"Mr\")" } ] }`,
+		},
+		{
+			description: `block macro call`,
+			input:       `#@wrap("div")content#end`,
+			output:      `{ "Stmt": [ { "Name": "wrap", "Args": [ { "Value": "div" } ], "Body": { "Stmt": [ { "Append": "content" } ] } } ] }`,
+		},
+		{
+			description: `list literal`,
+			input:       `#set($l = [1, "a", $x])`,
+			output:      `{ "Stmt": [ { "X": { "ID": "l" }, "Op": "=", "Y": { "Elements": [ { "Value": "1" }, { "Value": "a" }, { "ID": "x" } ] } } ] }`,
+		},
+		{
+			description: `map literal`,
+			input:       `#set($m = {"a": 1, "b": [$x]})`,
+			output:      `{ "Stmt": [ { "X": { "ID": "m" }, "Op": "=", "Y": { "Keys": [ { "Value": "a" }, { "Value": "b" } ], "Values": [ { "Value": "1" }, { "Elements": [ { "ID": "x" } ] } ] } } ] }`,
+		},
+		{
+			description: `foreach list literal`,
+			input:       `#foreach($v in ["a", "b"])$v#end`,
+			output:      `{ "Stmt": [ { "Item": { "ID": "v" }, "Set": { "Elements": [ { "Value": "a" }, { "Value": "b" } ] }, "Body": { "Stmt": [ { "ID": "v" } ] } } ] }`,
+		},
+		{
+			description: `loop control`,
+			input:       `#foreach($i in $a)#if($i)#break#end#continue #stop#end`,
+			output:      `{ "Stmt": [ { "Item": { "ID": "i" }, "Body": { "Stmt": [ { "Condition": { "ID": "i" }, "Body": { "Stmt": [ {} ] } }, {}, { "Append": " " }, {} ] } } ] }`,
+		},
+		{
+			description: `foreach else`,
+			input:       `#foreach($i in $a)#if($i)x#else-#end#else empty#end`,
+			output:      `{ "Stmt": [ { "Item": { "ID": "i" }, "Body": { "Stmt": [ { "Condition": { "ID": "i" }, "Body": { "Stmt": [ { "Append": "x" } ] }, "Else": { "Body": { "Stmt": [ { "Append": "-" } ] } } } ] }, "Else": { "Stmt": [ { "Append": " empty" } ] } } ] }`,
+		},
+		{
+			description: `block comment`,
+			input:       "a#* $x\n#if *#b ## line\nc",
+			output:      `{ "Stmt": [ { "Append": "a" }, { "Append": "b " }, { "Append": "c" } ] }`,
+		},
+		{
+			description: `string interpolation`,
+			input:       `#set($v = "Hi $name!" + 'raw $x')`,
+			output:      `{ "Stmt": [ { "X": { "ID": "v" }, "Op": "=", "Y": { "Token": "+", "X": { "Token": "+", "X": { "Token": "+", "X": { "Value": "Hi " }, "Y": { "ID": "name" } }, "Y": { "Value": "!" } }, "Y": { "Value": "raw $x" } } } ] }`,
+		},
+		{
+			description: `function call with single number argument`,
+			input:       `$time.Unix(5)`,
+			output:      `{ "Stmt": [ { "ID": "time", "X": { "ID": "Unix", "X": { "Args": [ { "Value": "5" } ] } } } ] }`,
+		},
+		{
+			description: `map literal missing colon`,
+			input:       `#set($m = {"a" 1})`,
+			output:      `{ "Stmt": [ { "Append": "#set($m = {\"a\" 1})" } ] }`,
+		},
 	}
 
 	//for i, useCase := range useCases[len(useCases)-1:] {
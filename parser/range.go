@@ -12,11 +12,11 @@ func matchForEach(cursor *parsly.Cursor) (*stmt.ForEach, error) {
 	if err != nil {
 		return nil, err
 	}
-	candidates := []*parsly.Token{ComaTerminator}
+	candidates := []*parsly.Token{ComaSeparator}
 	matched := cursor.MatchAfterOptional(WhiteSpace, candidates...)
 
 	var index *expr.Select
-	if matched.Code == comaToken {
+	if matched.Code == comaSeparatorToken {
 		index, err = matchVariable(cursor)
 		if err != nil {
 			return nil, err
@@ -5,7 +5,7 @@ import (
 	"github.com/viant/parsly"
 	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/expr"
-	"strconv"
+	"github.com/viant/velty/parser/matcher"
 	"strings"
 )
 
@@ -26,40 +26,52 @@ func matchRangeable(cursor *parsly.Cursor) (ast.Expression, error) {
 	case selectorStartToken:
 		return MatchSelector(cursor)
 	case squareBracketsToken:
-		text := matched.Text(cursor)
-		rangeCursor := parsly.NewCursor("", []byte(text[1:len(text)-1]), 0)
-
-		return matchRange(rangeCursor)
+		return matchSquareBrackets(matched.Text(cursor))
 	}
 	return nil, cursor.NewError(candidates...)
 }
 
 func matchRange(cursor *parsly.Cursor) (ast.Expression, error) {
-	variables := strings.Split(string(cursor.Input), "...")
-	if len(variables) != 2 {
-		return nil, fmt.Errorf("range expected to have two number literals but got %v", variables)
+	content := string(cursor.Input)
+	separator, inclusive := "..", true
+	if strings.Contains(content, "...") {
+		separator, inclusive = "...", false
 	}
 
-	begin, err := strconv.Atoi(variables[0])
-	if err != nil {
+	bounds := strings.SplitN(content, separator, 2)
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("range expected to have two bounds but got %v", content)
+	}
+
+	result := &expr.Range{Inclusive: inclusive}
+	var err error
+	if result.X, err = matchRangeBound(bounds[0]); err != nil {
 		return nil, err
 	}
 
-	finish, err := strconv.Atoi(variables[1])
+	if result.Y, err = matchRangeBound(bounds[1]); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func matchRangeBound(bound string) (ast.Expression, error) {
+	boundCursor := parsly.NewCursor("", []byte(strings.TrimSpace(bound)), 0)
+	_, expression, err := matchOperand(boundCursor, Number)
 	if err != nil {
 		return nil, err
 	}
 
-	return &expr.Range{
-		X: expr.Number(begin),
-		Y: expr.Number(finish),
-	}, nil
+	if boundCursor.Pos < boundCursor.InputSize {
+		return nil, fmt.Errorf("invalid range bound: %v", bound)
+	}
+	return expression, nil
 }
 
 func MatchSelector(cursor *parsly.Cursor) (*expr.Select, error) {
 	selectorStart := cursor.Pos
-	matched := cursor.MatchOne(Negation) // Java velocity supports `$!`. If String is null, then it will be replaced with Empty String. In Go - we don't need that
-	matched = cursor.MatchOne(SelectorBlock)
+	quiet := cursor.MatchOne(Negation).Code == negationToken // quiet reference `$!foo` renders empty if unresolved
+	matched := cursor.MatchOne(SelectorBlock)
 
 	if matched.Code == selectorBlockToken {
 		ID := matched.Text(cursor)
@@ -69,11 +81,19 @@ func MatchSelector(cursor *parsly.Cursor) (*expr.Select, error) {
 			return nil, err
 		}
 
+		if selectorCursor.MatchAfterOptional(WhiteSpace, Pipe).Code == pipeToken { // alternate value i.e. ${name|'anonymous'}
+			if _, result.Default, err = matchOperand(selectorCursor, String, Boolean, Number); err != nil {
+				return nil, err
+			}
+			selectorCursor.MatchOne(WhiteSpace)
+		}
+
 		if selectorCursor.Pos < selectorCursor.InputSize {
 			return nil, fmt.Errorf("expected to match all data, but couldn't match %v", string(cursor.Input[cursor.Pos:]))
 		}
 
 		result.FullName = "$" + ID
+		result.Quiet = quiet
 		return result, err
 
 	}
@@ -99,6 +119,7 @@ func MatchSelector(cursor *parsly.Cursor) (*expr.Select, error) {
 		}
 
 		selector.FullName = "$" + string(cursor.Input[selectorStart:cursor.Pos])
+		selector.Quiet = quiet
 		return selector, nil
 	}
 
@@ -106,11 +127,27 @@ func MatchSelector(cursor *parsly.Cursor) (*expr.Select, error) {
 }
 
 func matchCall(cursor *parsly.Cursor) (ast.Expression, error) {
-	candidates := []*parsly.Token{Parentheses, Dot, SquareBrackets}
+	candidates := []*parsly.Token{Parentheses, Dot, SafeDot, SquareBrackets}
 	matched := cursor.MatchAny(candidates...)
 	switch matched.Code {
 	case dotToken:
+		if cursor.Pos >= cursor.InputSize || !matcher.IsLetter(cursor.Input[cursor.Pos]) { // i.e. "Hello $name."
+			cursor.Pos--
+			return nil, nil
+		}
 		return MatchSelector(cursor)
+	case safeDotToken:
+		if cursor.Pos >= cursor.InputSize || !matcher.IsLetter(cursor.Input[cursor.Pos]) { // i.e. "Is it $name?."
+			cursor.Pos -= 2
+			return nil, nil
+		}
+
+		selector, err := MatchSelector(cursor)
+		if err != nil {
+			return nil, err
+		}
+		selector.NullSafe = true
+		return selector, nil
 	case parenthesesToken:
 		id := matched.Text(cursor)
 		newCursor := parsly.NewCursor("", []byte(id[1:len(id)-1]), 0)
@@ -174,7 +211,7 @@ func parseIdentity(cursor *parsly.Cursor) (*expr.Select, error) {
 func matchFunctionCall(cursor *parsly.Cursor) (*expr.Call, error) {
 	expressions := make([]ast.Expression, 0)
 
-	for cursor.Pos < cursor.InputSize-1 {
+	for cursor.Pos < cursor.InputSize && !isBlank(string(cursor.Input[cursor.Pos:])) {
 		argumentCursor := extractArgument(cursor)
 		_, expression, err := matchOperand(argumentCursor, String, Boolean, Number)
 		if err != nil {
@@ -28,21 +28,21 @@ func normalizeTokensIfNeeded(variable ast2.Expression, token ast2.Token, rightOp
 func matchToken(matched *parsly.TokenMatch) ast2.Token {
 	var token ast2.Token
 	switch matched.Code {
-	case equalToken:
+	case equalToken, eqWordToken:
 		token = ast2.EQ
-	case greaterToken:
+	case greaterToken, gtWordToken:
 		token = ast2.GTR
-	case lessToken:
+	case lessToken, ltWordToken:
 		token = ast2.LSS
-	case lessEqualToken:
+	case lessEqualToken, leWordToken:
 		token = ast2.LEQ
-	case greaterEqualToken:
+	case greaterEqualToken, geWordToken:
 		token = ast2.GTE
-	case notEqualToken:
+	case notEqualToken, neWordToken:
 		token = ast2.NEQ
-	case orToken:
+	case orToken, orWordToken:
 		token = ast2.OR
-	case andToken:
+	case andToken, andWordToken:
 		token = ast2.AND
 	case addToken:
 		token = ast2.ADD
@@ -52,9 +52,11 @@ func matchToken(matched *parsly.TokenMatch) ast2.Token {
 		token = ast2.MUL
 	case quoToken:
 		token = ast2.QUO
+	case modToken:
+		token = ast2.MOD
 	case assignToken:
 		token = ast2.ASSIGN
-	case negationToken:
+	case negationToken, notWordToken:
 		token = ast2.NEG
 	}
 	return token
@@ -1,6 +1,7 @@
 package velty
 
 import (
+	"errors"
 	"fmt"
 	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/expr"
@@ -19,6 +20,8 @@ const (
 
 var TimeType = reflect.TypeOf(time.Time{})
 
+var errNotFound = errors.New("not found")
+
 type (
 	Planner struct {
 		bufferSize int
@@ -27,9 +30,29 @@ type (
 		selectors *op.Selectors
 		constants *constants
 		*op.Functions
-		cache        *cache
-		escapeHTML   bool
-		panicOnError bool
+		cache              *cache
+		escapeHTML         bool
+		panicOnError       bool
+		macros             map[string]*macro
+		loader             Loader
+		parsing            []string
+		templateName       string
+		source             *ast.Source
+		pos                *ast.Pos
+		referenceMode      ReferenceMode
+		loops              []*loopScope
+		sortMapKeys        bool
+		spaceGobbling      SpaceGobbling
+		propertyResolution PropertyResolution
+		truthiness         Truthiness
+		zeroIsTrue         bool
+		timeLayout         string
+		scoping            VariableScoping
+		scopes             []*blockScope
+		bindings           []*binding
+		typeParser         TypeParser
+		inputs             []*Input
+		sections           map[string]*sourced
 	}
 )
 
@@ -56,7 +79,7 @@ func (p *Planner) addSelectors(prefix string, field reflect.StructField, fieldNa
 }
 
 func (p *Planner) createSelectors(prefix string, field reflect.StructField, parent *op.Selector, offsetSoFar, initialOffset uintptr, indirect bool, cycleDetector *CycleDetector, fieldName string) error {
-	cycleNode, cycleSelector := p.cycle(cycleDetector, field, parent)
+	cycleNode, cycleSelector, isCycle := p.cycle(cycleDetector, field, parent)
 
 	if field.Anonymous {
 		initialOffset += field.Offset
@@ -66,7 +89,7 @@ func (p *Planner) createSelectors(prefix string, field reflect.StructField, pare
 	vTag := Parse(field.Tag.Get(velty))
 
 	newParent, err := p.indexSelectorIfNeeded(prefix, field, parent, offsetSoFar, initialOffset, indirect, cycleSelector, fieldName)
-	if err != nil || cycleSelector != nil {
+	if err != nil || isCycle {
 		return err
 	}
 
@@ -93,12 +116,12 @@ func (p *Planner) createSelectors(prefix string, field reflect.StructField, pare
 	return p.addChildrenSelectors(prefix, field, offsetSoFar, initialOffset, indirect, cycleNode, newParent)
 }
 
-func (p *Planner) cycle(cycleDetector *CycleDetector, field reflect.StructField, parent *op.Selector) (*CycleDetector, *op.Selector) {
+func (p *Planner) cycle(cycleDetector *CycleDetector, field reflect.StructField, parent *op.Selector) (*CycleDetector, *op.Selector, bool) {
 	child, cycle := cycleDetector.Child(field.Type, parent)
 	if cycle {
-		return child, child.parentSelector
+		return child, child.parentSelector, true
 	}
-	return child, nil
+	return child, nil, false
 }
 
 func (p *Planner) addChildrenSelectors(holderPrefix string, field reflect.StructField, offsetSoFar, initialOffset uintptr, indirect bool, detector *CycleDetector, parent *op.Selector) error {
@@ -171,7 +194,7 @@ func elemIfNeeded(rType reflect.Type) (reflect.Type, bool) {
 // DefineVariable enrich the Type by adding field with given name.
 // val can be either of the reflect.Type or regular type (i.e. Foo)
 func (p *Planner) DefineVariable(name string, v interface{}, names ...string) error {
-	if p.selectorByName(name) != nil {
+	if p.isVariable(name) {
 		return nil
 	}
 
@@ -229,6 +252,7 @@ func (p *Planner) matchFirstSelector(selector *expr.Select) (*op.Selector, ast.E
 
 	resultSelector := p.selectorByName(selector.ID)
 	if resultSelector != nil {
+		p.markLoopUsage(selector.ID)
 		return resultSelector, selector.X, nil
 	}
 
@@ -249,30 +273,131 @@ func (p *Planner) matchSelector(call ast.Expression, resultSelector *op.Selector
 
 	switch actual := call.(type) {
 	case *expr.Select:
-		if callSelector, callNext, callErr := p.tryMatchCall(actual.X, resultSelector, actual.ID); callSelector != nil || callErr != nil {
-			return callSelector, callNext, callErr
+		if call, ok := actual.X.(*expr.Call); ok {
+			if callSelector, callNext, callErr := p.tryMatchCall(call, resultSelector, actual.ID); callSelector != nil || callErr != nil {
+				return callSelector, callNext, callErr
+			}
+		}
+
+		if isDynamic(resultSelector.Type) {
+			return p.matchProperty(actual, resultSelector, selectorId)
+		}
+
+		fieldSelector, err := p.matchField(parentType, actual, selectorId)
+		if err == nil {
+			return fieldSelector, actual.X, nil
+		}
+
+		if errors.Is(err, errNotFound) && p.propertyResolution == JavaBeanProperties {
+			if getterSelector, next, getterErr := p.matchGetter(actual, resultSelector); getterSelector != nil || getterErr != nil {
+				return getterSelector, next, getterErr
+			}
 		}
+		return nil, nil, err
+	}
+
+	return resultSelector, nil, nil
+}
+
+//isDynamic returns true if properties of the rType value are resolved as map keys or at runtime, i.e. map[string]T or interface{}
+func isDynamic(rType reflect.Type) bool {
+	if rType == nil {
+		return false
+	}
 
-		_, err = p.fieldByName(parentType, actual, actual.ID)
+	switch rType.Kind() {
+	case reflect.Map:
+		return rType.Key().Kind() == reflect.String
+	case reflect.Interface:
+		return true
+	}
+	return false
+}
+
+//matchProperty matches map entry or dynamic value property, i.e. $cfg.db.host
+func (p *Planner) matchProperty(actual *expr.Select, resultSelector *op.Selector, selectorId string) (*op.Selector, ast.Expression, error) {
+	if resultSelector.Type.Kind() == reflect.Map {
+		mapSelector, err := p.newMapSelector(selectorId, &expr.SliceIndex{X: expr.StringLiteral(actual.ID)}, resultSelector)
 		if err != nil {
 			return nil, nil, err
 		}
+		return mapSelector, actual.X, nil
+	}
+
+	xOperand, err := op.NewExpression(resultSelector).Operand(*p.Control)
+	if err != nil {
+		return nil, nil, err
+	}
+	return op.NewPropertySelector(selectorId, actual.ID, xOperand, resultSelector), actual.X, nil
+}
+
+//matchField returns selector of the field matching the property, with JavaBeanProperties first letter case is ignored
+func (p *Planner) matchField(parentType reflect.Type, actual *expr.Select, selectorId string) (*op.Selector, error) {
+	var result error
+	for _, name := range p.propertyNames(actual.ID) {
+		_, err := p.fieldByName(parentType, name)
+		if err == nil {
+			fieldId := selectorId + fieldSeparator + name
+			if fieldSelector, found := p.selectors.ById(fieldId); found {
+				return fieldSelector, nil
+			}
+			err = fmt.Errorf("%w selector for the %v", errNotFound, strings.ReplaceAll(fieldId, fieldSeparator, "."))
+		}
+
+		if !errors.Is(err, errNotFound) {
+			return nil, err
+		}
 
-		selectorId = selectorId + fieldSeparator + actual.ID
-		var found bool
-		resultSelector, found = p.selectors.ById(selectorId)
-		if !found {
-			return nil, nil, fmt.Errorf("not found selector for the %v", strings.ReplaceAll(selectorId, fieldSeparator, "."))
+		if result == nil {
+			result = err
 		}
+	}
+	return nil, result
+}
 
-		return resultSelector, actual.X, nil
+//matchGetter matches zero argument GetX, IsX or X method of the JavaBean property x
+func (p *Planner) matchGetter(actual *expr.Select, resultSelector *op.Selector) (*op.Selector, ast.Expression, error) {
+	if resultSelector.Type == nil {
+		return nil, nil, nil
 	}
 
-	return resultSelector, nil, nil
+	property := exportedName(actual.ID)
+	for _, methodName := range []string{"Get" + property, "Is" + property, property} {
+		method, ok := resultSelector.Type.MethodByName(methodName)
+		if !ok || method.Type.NumIn() != 1 || method.Type.NumOut() == 0 {
+			continue
+		}
+
+		return p.matchFunc(methodName, &expr.Call{X: actual.X}, resultSelector)
+	}
+	return nil, nil, nil
+}
+
+//propertyNames returns field names matching the template property name
+func (p *Planner) propertyNames(name string) []string {
+	if p.propertyResolution != JavaBeanProperties {
+		return []string{name}
+	}
+
+	result := []string{name}
+	for _, candidate := range []string{exportedName(name), strings.ToLower(name[:1]) + name[1:]} {
+		if candidate != name {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+func exportedName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
 }
 
-func (p *Planner) fieldByName(parentType reflect.Type, actual *expr.Select, selectorId string) (*xunsafe.Field, error) {
-	field := xunsafe.FieldByName(parentType, actual.ID)
+func (p *Planner) fieldByName(parentType reflect.Type, name string) (*xunsafe.Field, error) {
+	if parentType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w field %v at %v", errNotFound, name, parentType.String())
+	}
+
+	field := xunsafe.FieldByName(parentType, name)
 	if field != nil {
 		if Parse(field.Tag.Get(velty)).Omit {
 			return nil, fmt.Errorf("can't create selector for field %v", field.Name)
@@ -282,12 +407,12 @@ func (p *Planner) fieldByName(parentType reflect.Type, actual *expr.Select, sele
 
 	for i := 0; i < parentType.NumField(); i++ {
 		vTag := Parse(parentType.Field(i).Tag.Get(velty))
-		if vTag.nameEqual(actual.ID) {
+		if vTag.nameEqual(name) {
 			return xunsafe.NewField(parentType.Field(i)), nil
 		}
 	}
 
-	return nil, fmt.Errorf("not found field %v at %v", strings.ReplaceAll(selectorId, fieldSeparator, "."), parentType.String())
+	return nil, fmt.Errorf("%w field %v at %v", errNotFound, name, parentType.String())
 }
 
 func deref(rType reflect.Type) reflect.Type {
@@ -349,6 +474,16 @@ func (p *Planner) validateSelector(sel *op.Selector) error {
 	return nil
 }
 
+func (p *Planner) isFuncNs(selector *op.Selector) bool {
+	return selector.Parent == nil && selector.Literal != nil && p.IsFuncNs(selector.ID)
+}
+
+//isVariable returns true if variable was defined, variables shadow function namespaces i.e. $date
+func (p *Planner) isVariable(name string) bool {
+	_, ok := p.selectors.Index[name]
+	return ok
+}
+
 func (p *Planner) selectorByName(name string) *op.Selector {
 	if idx, ok := p.selectors.Index[name]; ok {
 		return p.selectors.Selector(idx)
@@ -439,6 +574,7 @@ func New(options ...Option) *Planner {
 		selectors: op.NewSelectors(),
 		cache:     newCache(0),
 		constants: newConstants(),
+		macros:    map[string]*macro{},
 	}
 
 	planner.init(options)
@@ -448,14 +584,29 @@ func New(options ...Option) *Planner {
 
 func (p *Planner) New() *Planner {
 	scope := &Planner{
-		bufferSize: p.bufferSize,
-		Control:    p.Control,
-		Type:       p.Type.Snapshot(),
-		selectors:  p.selectors.Snapshot(),
-		constants:  p.constants,
-		Functions:  p.Functions,
-		cache:      p.cache,
-		escapeHTML: p.escapeHTML,
+		bufferSize:         p.bufferSize,
+		Control:            new(est.Control),
+		Type:               p.Type.Snapshot(),
+		selectors:          p.selectors.Snapshot(),
+		constants:          p.constants,
+		Functions:          p.Functions,
+		cache:              p.cache,
+		escapeHTML:         p.escapeHTML,
+		panicOnError:       p.panicOnError,
+		referenceMode:      p.referenceMode,
+		sortMapKeys:        p.sortMapKeys,
+		spaceGobbling:      p.spaceGobbling,
+		propertyResolution: p.propertyResolution,
+		truthiness:         p.truthiness,
+		zeroIsTrue:         p.zeroIsTrue,
+		timeLayout:         p.timeLayout,
+		scoping:            p.scoping,
+		typeParser:         p.typeParser,
+		inputs:             append([]*Input{}, p.inputs...),
+		macros:             p.macrosSnapshot(),
+		loader:             p.loader,
+		parsing:            append([]string{}, p.parsing...),
+		source:             p.source,
 	}
 
 	return scope
@@ -476,6 +627,28 @@ func (p *Planner) apply(options []Option) {
 			if p.Functions == nil {
 				p.Functions = actual
 			}
+		case Loader:
+			p.loader = actual
+		case TemplateName:
+			p.templateName = string(actual)
+		case ReferenceMode:
+			p.referenceMode = actual
+		case SortMapKeys:
+			p.sortMapKeys = bool(actual)
+		case SpaceGobbling:
+			p.spaceGobbling = actual
+		case PropertyResolution:
+			p.propertyResolution = actual
+		case Truthiness:
+			p.truthiness = actual
+		case ZeroIsTrue:
+			p.zeroIsTrue = bool(actual)
+		case TimeLayout:
+			p.timeLayout = string(actual)
+		case VariableScoping:
+			p.scoping = actual
+		case TypeParser:
+			p.typeParser = actual
 		}
 	}
 }
@@ -628,10 +801,31 @@ func (p *Planner) tryMatchFunc(ID string, x ast.Expression) (*op.Selector, ast.E
 }
 
 func (p *Planner) matchFunc(ID string, actual *expr.Call, selector *op.Selector) (*op.Selector, ast.Expression, error) {
-	callSelector, err := p.newFuncSelector(ID, ID, actual, selector)
+	callSelector, err := p.newFuncSelector(ID, p.methodName(ID, selector), actual, selector)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	return callSelector, actual.X, nil
 }
+
+//methodName returns receiver method name, with JavaBeanProperties i.e. $order.isPaid() calls IsPaid method,
+//function namespaces methods are always matched this way, i.e. $date.format(...) calls Format
+func (p *Planner) methodName(ID string, selector *op.Selector) string {
+	if selector == nil || selector.Type == nil {
+		return ID
+	}
+
+	if p.propertyResolution != JavaBeanProperties && !p.isFuncNs(selector) {
+		return ID
+	}
+
+	if _, ok := selector.Type.MethodByName(ID); ok {
+		return ID
+	}
+
+	if _, ok := selector.Type.MethodByName(exportedName(ID)); ok {
+		return exportedName(ID)
+	}
+	return ID
+}
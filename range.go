@@ -1,41 +1,109 @@
 package velty
 
 import (
-	aexpr "github.com/viant/velty/ast/expr"
+	"fmt"
+	"github.com/viant/velty/ast/expr"
+	"github.com/viant/velty/ast/stmt"
+	"github.com/viant/velty/est"
+	eexpr "github.com/viant/velty/est/expr"
 	"github.com/viant/velty/est/op"
+	estmt "github.com/viant/velty/est/stmt"
+	types "github.com/viant/xunsafe/converter"
 	"reflect"
-	"strconv"
 	"unsafe"
 )
 
-func (p *Planner) compileRange(actual *aexpr.Range) (*op.Expression, error) {
-	begin, err := strconv.Atoi(actual.X.Value)
+func (p *Planner) compileRange(actual *expr.Range) (*op.Expression, error) {
+	from, to, err := p.compileRangeBounds(actual)
 	if err != nil {
 		return nil, err
 	}
 
-	finish, err := strconv.Atoi(actual.Y.Value)
-	if err != nil {
-		return nil, err
+	if from == nil {
+		return nil, fmt.Errorf("unresolved range bound")
 	}
 
-	var aSlice []int
-	if begin < finish {
-		aSlice = make([]int, finish-begin)
-		for i := 0; i < len(aSlice); i++ {
-			aSlice[i] = begin + i
+	sliceType := reflect.SliceOf(actual.Type())
+	if from.LiteralPtr != nil && to.LiteralPtr != nil {
+		begin, end := *(*int)(*from.LiteralPtr), *(*int)(*to.LiteralPtr)
+		step := estmt.RangeStep(begin, end)
+		if actual.Inclusive {
+			end += step
 		}
-	} else {
-		aSlice = make([]int, begin-finish)
-		for i := 0; i < len(aSlice); i++ {
-			aSlice[i] = begin - i
+
+		aSlice := make([]int, 0, (end-begin)*step)
+		for i := begin; i != end; i += step {
+			aSlice = append(aSlice, i)
 		}
+
+		p.registerConst(&aSlice)
+		slicePtr := unsafe.Pointer(&aSlice)
+		return &op.Expression{
+			LiteralPtr: &slicePtr,
+			Type:       sliceType,
+		}, nil
 	}
 
-	p.registerConst(&aSlice)
-	slicePtr := unsafe.Pointer(&aSlice)
+	acc := p.accumulator(sliceType)
 	return &op.Expression{
-		LiteralPtr: &slicePtr,
-		Type:       reflect.SliceOf(actual.Type()),
+		Type: sliceType,
+		New:  eexpr.Range(from, to, actual.Inclusive, &op.Expression{Selector: acc, Type: sliceType}),
 	}, nil
 }
+
+//compileRangeLoop compiles #foreach over the range, iterating without materializing a slice
+func (p *Planner) compileRangeLoop(actual *stmt.ForEach, aRange *expr.Range) (est.New, error) {
+	from, to, err := p.compileRangeBounds(aRange)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == nil {
+		return p.compileUnresolvedForEach(actual)
+	}
+
+	item, block, meta, control, err := p.compileItemBody(actual, aRange.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	elseBlock, err := p.compileForEachElse(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return estmt.RangeLoop(block, item, from, to, aRange.Inclusive, meta, elseBlock, control)
+}
+
+//compileRangeBounds compiles range bounds, returns nil bounds if any of them is unresolved
+func (p *Planner) compileRangeBounds(actual *expr.Range) (*op.Expression, *op.Expression, error) {
+	from, err := p.compileExpr(actual.X)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	to, err := p.compileExpr(actual.Y)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, bound := range []*op.Expression{from, to} {
+		if bound.Type == nil {
+			return nil, nil, nil
+		}
+
+		switch bound.Type.Kind() {
+		case reflect.Int:
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			unify, err := types.Unify(intType, bound.Type)
+			if err != nil {
+				return nil, nil, err
+			}
+			bound.Unify = unify.Y
+		default:
+			return nil, nil, fmt.Errorf("range bound has to be int, but had %v", bound.Type.String())
+		}
+	}
+	return from, to, nil
+}
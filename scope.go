@@ -0,0 +1,146 @@
+package velty
+
+import (
+	"github.com/viant/velty/ast"
+	"github.com/viant/velty/ast/expr"
+	"reflect"
+	"strings"
+)
+
+const globalVariable = "global"
+
+//blockScope represents #foreach or macro body lexical scope with BlockScoping
+type blockScope struct {
+	locals   map[string]bool
+	bindings []*binding
+}
+
+//enterScope starts #foreach or macro body lexical scope, returns nil unless BlockScoping is used
+func (p *Planner) enterScope() *blockScope {
+	if p.scoping != BlockScoping {
+		return nil
+	}
+
+	scope := &blockScope{locals: map[string]bool{}}
+	p.scopes = append(p.scopes, scope)
+	return scope
+}
+
+//exitScope restores outer variables shadowed by the scope local variables
+func (p *Planner) exitScope(scope *blockScope) {
+	if scope == nil {
+		return
+	}
+
+	for i := len(scope.bindings) - 1; i >= 0; i-- {
+		p.unbind(scope.bindings[i])
+	}
+	p.scopes = p.scopes[:len(p.scopes)-1]
+}
+
+func (p *Planner) innerScope() *blockScope {
+	if len(p.scopes) == 0 {
+		return nil
+	}
+	return p.scopes[len(p.scopes)-1]
+}
+
+//declareLocal marks variable bound by the caller, i.e. macro parameter, as local to the current scope
+func (p *Planner) declareLocal(name string) {
+	if scope := p.innerScope(); scope != nil {
+		scope.locals[name] = true
+	}
+}
+
+//defineLocal defines variable local to the current scope, shadowing outer one, outside of the scope variable is defined globally
+func (p *Planner) defineLocal(name string, rType reflect.Type) error {
+	scope := p.innerScope()
+	if scope == nil {
+		return p.DefineVariable(name, rType)
+	}
+
+	if scope.locals[name] {
+		return nil
+	}
+
+	aBinding, _, err := p.bind(name, rType)
+	if err != nil {
+		return err
+	}
+
+	scope.locals[name] = true
+	scope.bindings = append(scope.bindings, aBinding)
+	return nil
+}
+
+//globalSelect returns reference without the $global prefix, i.e. $global.total is resolved as $total in the template scope
+func (p *Planner) globalSelect(e ast.Expression) (*expr.Select, bool) {
+	selector, ok := e.(*expr.Select)
+	if !ok || p.scoping != BlockScoping || selector.ID != globalVariable || p.isVariable(globalVariable) {
+		return nil, false
+	}
+
+	inner, ok := selector.X.(*expr.Select)
+	if !ok {
+		return nil, false
+	}
+
+	result := *inner
+	result.FullName = selector.FullName
+	result.Quiet = selector.Quiet
+	result.Default = selector.Default
+	return &result, true
+}
+
+//inGlobalScope runs fn with the template scope variables visible, variables defined by fn are added to the template scope
+func (p *Planner) inGlobalScope(fn func() error) error {
+	index := p.selectors.Index
+	global := p.globalIndex()
+	p.selectors.Index = global
+	defined := make(map[string]int, len(global))
+	for id, idx := range global {
+		defined[id] = idx
+	}
+
+	err := fn()
+	p.selectors.Index = index
+	for id, idx := range global {
+		if _, ok := defined[id]; !ok {
+			p.addGlobal(id, idx)
+		}
+	}
+	return err
+}
+
+//globalIndex returns selectors index of the template scope, as if all active bindings were restored
+func (p *Planner) globalIndex() map[string]int {
+	result := make(map[string]int, len(p.selectors.Index))
+	for id, idx := range p.selectors.Index {
+		result[id] = idx
+	}
+
+	for i := len(p.bindings) - 1; i >= 0; i-- {
+		aBinding := p.bindings[i]
+		for id, idx := range aBinding.bound {
+			if result[id] == idx {
+				delete(result, id)
+			}
+		}
+
+		for id, idx := range aBinding.hidden {
+			result[id] = idx
+		}
+	}
+	return result
+}
+
+//addGlobal adds template scope selector, if it is shadowed it becomes visible once the first shadowing binding is restored
+func (p *Planner) addGlobal(id string, idx int) {
+	for _, aBinding := range p.bindings {
+		if id == aBinding.name || strings.HasPrefix(id, aBinding.name+fieldSeparator) {
+			aBinding.hidden[id] = idx
+			return
+		}
+	}
+	p.selectors.Index[id] = idx
+}
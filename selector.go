@@ -1,18 +1,42 @@
 package velty
 
 import (
+	"errors"
+	"fmt"
+	"github.com/viant/velty/ast"
 	"github.com/viant/velty/ast/expr"
 	"github.com/viant/velty/est"
+	eexpr "github.com/viant/velty/est/expr"
 	"github.com/viant/velty/est/op"
 	"github.com/viant/velty/est/stmt"
+	types "github.com/viant/xunsafe/converter"
+	"reflect"
 )
 
 func (p *Planner) selectorExpr(selector *expr.Select) (*op.Expression, error) {
+	if global, ok := p.globalSelect(selector); ok {
+		var result *op.Expression
+		err := p.inGlobalScope(func() (err error) {
+			result, err = p.selectorExpr(global)
+			return err
+		})
+		return result, err
+	}
+
 	var err error
 	expression := &op.Expression{}
 	expression.Selector, err = p.selector(selector)
 	if err != nil {
-		return nil, err
+		if !errors.Is(err, errNotFound) || (p.referenceMode == StrictReferences && !selector.Quiet && selector.Default == nil) {
+			return nil, err
+		}
+		expression.Selector = nil
+	}
+
+	for sel := expression.Selector; sel != nil; sel = sel.Parent {
+		if sel.Func != nil && sel.Location == nil {
+			sel.Location = p.location(selector.FullName)
+		}
 	}
 
 	if expression.Selector == nil {
@@ -25,12 +49,79 @@ func (p *Planner) selectorExpr(selector *expr.Select) (*op.Expression, error) {
 	return expression, nil
 }
 
+//referenceExpr compiles selector which value is read, in strict mode the selector has to be resolved unless it defines alternate value
+func (p *Planner) referenceExpr(selector *expr.Select) (*op.Expression, error) {
+	expression, err := p.selectorExpr(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if selector.Default != nil || (expression.Type != nil && isNullSafe(selector)) {
+		return p.alternateExpr(selector, expression)
+	}
+
+	if expression.Type == nil && p.referenceMode == StrictReferences && !selector.Quiet {
+		return nil, fmt.Errorf("unresolved reference %v", selector.FullName)
+	}
+	return expression, nil
+}
+
 func (p *Planner) compileStmtSelector(actual *expr.Select) (est.New, error) {
-	selExpr, err := p.selectorExpr(actual)
+	selExpr, err := p.referenceExpr(actual)
 	if err != nil {
 		return nil, err
 	}
 
+	if selExpr.Type == nil && (actual.Quiet || p.referenceMode == EmptyReferences) {
+		return nop(), nil
+	}
+
 	p.Type.ValueAccessor(actual.ID)
 	return stmt.Selector(selExpr, false), nil
 }
+
+//alternateExpr compiles null-safe selector i.e. $order?.Customer?.Name, and selector with alternate value i.e. ${name|'anonymous'}
+func (p *Planner) alternateExpr(selector *expr.Select, x *op.Expression) (*op.Expression, error) {
+	x.NullSafe = isNullSafe(selector)
+	if selector.Default == nil {
+		return &op.Expression{Type: x.Type, New: eexpr.Alternate(x, nil, nil, p.zeroIsTrue)}, nil
+	}
+
+	alternate, err := p.compileExpr(selector.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.Type == nil {
+		return alternate, nil
+	}
+
+	if alternate.Type == nil {
+		return nil, fmt.Errorf("unresolved alternate value of %v", selector.FullName)
+	}
+
+	if x.Type.Kind() == reflect.Interface { // dynamic value, i.e. ${cfg.db.user|'root'}
+		alternate.Unify = eexpr.Box(alternate.Type)
+		return &op.Expression{Type: x.Type, New: eexpr.Alternate(x, nil, alternate, p.zeroIsTrue)}, nil
+	}
+
+	unify, err := types.NormalizeAndUnify(x.Type, alternate.Type)
+	if err != nil {
+		return nil, fmt.Errorf("incompatible alternate value of %v: %w", selector.FullName, err)
+	}
+
+	alternate.Unify = unify.Y
+	return &op.Expression{Type: unify.RType, New: eexpr.Alternate(x, unify.X, alternate, p.zeroIsTrue)}, nil
+}
+
+func isNullSafe(e ast.Expression) bool {
+	switch actual := e.(type) {
+	case *expr.Select:
+		return actual.NullSafe || isNullSafe(actual.X)
+	case *expr.Call:
+		return isNullSafe(actual.X)
+	case *expr.SliceIndex:
+		return isNullSafe(actual.Y)
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+//go:build go1.23
+
+package velty_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPlanner_CompileSeq(t *testing.T) {
+	var testCases = []testdata{
+		{
+			description: "foreach seq",
+			template:    `#foreach($v in $seq)$v#if($foreach.hasNext),#end#end`,
+			definedVars: map[string]interface{}{"seq": intSeq(3)},
+			expect:      "1,2,3",
+		},
+		{
+			description: "foreach seq break",
+			template:    `#foreach($v in $seq)#if($v == 3)#break#end$v#end`,
+			definedVars: map[string]interface{}{"seq": intSeq(100)},
+			expect:      "12",
+		},
+		{
+			description: "foreach seq2",
+			template:    `#foreach($e in $seq)$e.Key:$e.Value #end`,
+			definedVars: map[string]interface{}{"seq": namesSeq2("a", "b")},
+			expect:      "0:a 1:b ",
+		},
+		{
+			description: "foreach empty seq",
+			template:    `#foreach($v in $seq)$v#else empty#end`,
+			definedVars: map[string]interface{}{"seq": intSeq(0)},
+			expect:      " empty",
+		},
+		{
+			description: "foreach seq executed twice",
+			template:    `#foreach($i in [1..2])#foreach($v in $seq)$v#end#end`,
+			definedVars: map[string]interface{}{"seq": intSeq(2)},
+			expect:      "1212",
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec, state, err := testCase.init(t)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+
+		err = exec.Exec(state)
+		assert.Nil(t, err, testCase.description)
+		assert.Equal(t, testCase.expect, state.Buffer.String(), testCase.description)
+	}
+}
+
+func intSeq(count int) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 1; i <= count; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func namesSeq2(names ...string) func(yield func(int, string) bool) {
+	return func(yield func(int, string) bool) {
+		for i, name := range names {
+			if !yield(i, name) {
+				return
+			}
+		}
+	}
+}
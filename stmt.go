@@ -6,6 +6,7 @@ import (
 	"github.com/viant/velty/ast/expr"
 	stmt2 "github.com/viant/velty/ast/stmt"
 	"github.com/viant/velty/est"
+	"github.com/viant/velty/est/op"
 	"github.com/viant/velty/est/stmt"
 	"github.com/viant/velty/est/stmt/assign"
 	"github.com/viant/xunsafe/converter"
@@ -20,6 +21,9 @@ func (p *Planner) compileStmt(statement ast.Statement) (est.New, error) {
 	case *stmt2.Append:
 		return p.compileAppend(actual)
 	case *expr.Select:
+		if slot, ok := p.isBodyContent(actual); ok {
+			return stmt.BodyContent(slot), nil
+		}
 		return p.compileStmtSelector(actual)
 	case *stmt2.Block:
 		return p.compileStmt(actual.Stmt)
@@ -33,22 +37,50 @@ func (p *Planner) compileStmt(statement ast.Statement) (est.New, error) {
 		return p.compileBlock(&stmt2.Block{Stmt: actual})
 	case *stmt2.Evaluate:
 		return p.compileEvaluate(actual)
+	case *stmt2.Parse:
+		return p.compileParse(actual)
+	case *stmt2.Include:
+		return p.compileInclude(actual)
+	case *stmt2.Param:
+		return p.compileParam(actual)
+	case *stmt2.NamedBlock:
+		return p.compileNamedBlock(actual)
+	case *sourced:
+		return p.compileSourced(actual)
+	case *stmt2.Extends:
+		return nil, fmt.Errorf("unsupported nested #extends, it has to be used at the template top level")
+	case *stmt2.Macro:
+		return p.compileMacro(actual)
+	case *stmt2.MacroCall:
+		return p.compileMacroCall(actual, false)
+	case *stmt2.BlockMacroCall:
+		return p.compileMacroCall(&actual.MacroCall, true)
+	case *stmt2.Break:
+		return p.compileInterrupt(est.HasBreak), nil
+	case *stmt2.Continue:
+		return p.compileInterrupt(est.HasContinue), nil
+	case *stmt2.Stop:
+		return p.compileInterrupt(est.HasStop), nil
 	}
 
 	return nil, fmt.Errorf("unsupported stmt: %T", statement)
 }
 
 func (p *Planner) computeAssignment(actual *stmt2.Statement) (est.New, error) {
-	x, err := p.compileExpr(actual.X)
+	y, err := p.compileExpr(actual.Y)
 	if err != nil {
 		return nil, err
 	}
 
-	y, err := p.compileExpr(actual.Y)
+	x, err := p.assignmentTarget(actual.X, y.Type)
 	if err != nil {
 		return nil, err
 	}
 
+	if x.Selector != nil && x.Selector.Map != nil {
+		return p.compileEntryAssignment(x, y)
+	}
+
 	if err = p.adjustSelector(x, y.Type); err != nil {
 		return nil, err
 	}
@@ -64,8 +96,88 @@ func (p *Planner) computeAssignment(actual *stmt2.Statement) (est.New, error) {
 	return assign.Assign(x, y)
 }
 
+//compileEntryAssignment compiles map entry assignment i.e. #set($m["key"] = 10)
+func (p *Planner) compileEntryAssignment(x, y *op.Expression) (est.New, error) {
+	aMap := x.Selector.Map
+	mapType := aMap.MapOperand().Type
+	if y.Type == nil {
+		return nil, fmt.Errorf("couldn't determine %v type", x.Selector.ID)
+	}
+
+	if elemType := mapType.Elem(); elemType.Kind() != reflect.Interface {
+		unify, err := converter.Unify(elemType, y.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		y.Unify = unify.Y
+		y.Type = unify.RType
+	}
+
+	return assign.Entry(aMap, mapType, y)
+}
+
+//assignmentTarget compiles assignment target, with BlockScoping #set($x = ...) defines x local to the #foreach or macro body,
+//#set($global.x = ...) assigns the template scope variable
+func (p *Planner) assignmentTarget(target ast.Expression, yType reflect.Type) (*op.Expression, error) {
+	if global, ok := p.globalSelect(target); ok {
+		var result *op.Expression
+		err := p.inGlobalScope(func() (err error) {
+			if result, err = p.compileTarget(global); err != nil || result.Selector.Map != nil {
+				return err
+			}
+			return p.adjustSelector(result, yType)
+		})
+		return result, err
+	}
+
+	if selector, ok := target.(*expr.Select); ok && selector.X == nil && yType != nil && p.innerScope() != nil {
+		if err := p.defineLocal(selector.ID, yType); err != nil {
+			return nil, err
+		}
+	}
+	return p.compileTarget(target)
+}
+
+//compileTarget compiles assignment target, which does not have to be defined
+func (p *Planner) compileTarget(target ast.Expression) (*op.Expression, error) {
+	selector, ok := target.(*expr.Select)
+	if !ok {
+		return p.compileExpr(target)
+	}
+
+	if selector.X == nil && p.IsFuncNs(selector.ID) && !p.isVariable(selector.ID) {
+		//variable shadows function namespace, i.e. #set($date = $order.Date)
+		return &op.Expression{Selector: op.NewSelector(selector.ID, selector.ID, nil, nil)}, nil
+	}
+
+	result, err := p.selectorExpr(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateTarget(result.Selector, selector.ID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//validateTarget checks if the value selected by the assignment target can be updated
+func validateTarget(selector *op.Selector, variable string) error {
+	for sel := selector; sel != nil; sel = sel.Parent {
+		switch {
+		case sel.Func != nil, sel.InterfaceExec != nil, sel.Property != nil, sel.Literal != nil:
+		case sel.Map != nil && sel != selector:
+		default:
+			continue
+		}
+		return fmt.Errorf("can't assign to non-addressable $%v target", variable)
+	}
+	return nil
+}
+
 func (p *Planner) compileIf(actual *stmt2.If) (est.New, error) {
-	cond, err := p.compileExpr(actual.Condition)
+	cond, err := p.compileCondition(actual.Condition)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +191,7 @@ func (p *Planner) compileIf(actual *stmt2.If) (est.New, error) {
 	if actual.Else != nil {
 		elseIf, err = p.compileStmt(actual.Else)
 		if err != nil {
-			return nil, err
+			return nil, p.locate(actual.Else, err)
 		}
 	}
 
@@ -103,44 +215,81 @@ func (p *Planner) compileForLoop(actual *stmt2.ForLoop) (est.New, error) {
 		return nil, err
 	}
 
-	block, err := p.newCompute(&actual.Body)
+	body, control, err := p.compileControlled(func() (est.New, error) {
+		return p.compileBlock(&actual.Body)
+	}, est.HasStop)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := body(control)
 	if err != nil {
 		return nil, err
 	}
 
-	return stmt.ForLoop(init, post, condition, block)
+	return stmt.ForLoop(init, post, condition, block, control)
 }
 
 func (p *Planner) compileForEachLoop(actual *stmt2.ForEach) (est.New, error) {
+	if aRange, ok := actual.Set.(*expr.Range); ok {
+		return p.compileRangeLoop(actual, aRange)
+	}
+
 	sliceSelector, err := p.compileExpr(actual.Set)
 	if err != nil {
 		return nil, err
 	}
 
 	if sliceSelector.Type == nil {
-		return nop(), nil
+		return p.compileUnresolvedForEach(actual)
 	}
 
-	if sliceSelector.Type.Kind() != reflect.Slice {
+	isSlice := sliceSelector.Type.Kind() == reflect.Slice
+	var itemType reflect.Type
+	var ok bool
+	if isSlice {
+		itemType = sliceSelector.Type.Elem()
+	} else if itemType, ok = stmt.IterationItemType(sliceSelector.Type); !ok {
 		return nil, fmt.Errorf("unsupported ForEach set type: %v", sliceSelector.Type.String())
 	}
 
-	itemType := sliceSelector.Type.Elem()
-
-	if err := p.DefineVariable(actual.Item.ID, itemType); err != nil {
+	selector, block, meta, control, err := p.compileItemBody(actual, itemType)
+	if err != nil {
 		return nil, err
 	}
 
-	selector, err := p.compileExpr(actual.Item)
+	elseBlock, err := p.compileForEachElse(actual)
 	if err != nil {
 		return nil, err
 	}
+	var loop est.New
+	if isSlice {
+		loop, err = stmt.ForEachLoop(block, selector, sliceSelector, meta, elseBlock, control)
+	} else {
+		var yield *op.Selector
+		if sliceSelector.Type.Kind() == reflect.Func {
+			yield = p.accumulator(stmt.YieldType)
+		}
+		loop, err = stmt.IterateLoop(block, selector, sliceSelector, yield, meta, elseBlock, p.sortMapKeys, control)
+	}
+	return loop, err
+}
+
+//compileForEachElse compiles #else branch of the #foreach, returns nil if there is none
+func (p *Planner) compileForEachElse(actual *stmt2.ForEach) (est.New, error) {
+	if actual.Else == nil {
+		return nil, nil
+	}
+	return p.compileBlock(actual.Else)
+}
 
-	block, err := p.compileBlock(&actual.Body)
-	if err != nil {
-		return nil, err
+//compileUnresolvedForEach compiles #foreach over unresolved collection, only #else branch is rendered
+func (p *Planner) compileUnresolvedForEach(actual *stmt2.ForEach) (est.New, error) {
+	elseBlock, err := p.compileForEachElse(actual)
+	if err != nil || elseBlock != nil {
+		return elseBlock, err
 	}
-	return stmt.ForEachLoop(block, selector, sliceSelector)
+	return nop(), nil
 }
 
 func nop() est.New {
@@ -162,6 +311,8 @@ func (p *Planner) compileAppend(actual *stmt2.Append) (est.New, error) {
 }
 
 func (p *Planner) compileEvaluate(actual *stmt2.Evaluate) (est.New, error) {
+	p.markLoopsUsed()
+	*p.Control |= est.HasStop
 	selector, err := p.compileExpr(actual.X)
 	if err != nil {
 		return nil, err
@@ -169,3 +320,9 @@ func (p *Planner) compileEvaluate(actual *stmt2.Evaluate) (est.New, error) {
 
 	return evaluate(selector, p.cache, p)
 }
+
+//compileInterrupt registers control request in the enclosing loop or macro body, so that its blocks are compiled as interruptible
+func (p *Planner) compileInterrupt(request est.Control) est.New {
+	*p.Control |= request
+	return stmt.Interrupt(request)
+}
@@ -0,0 +1,47 @@
+package velty
+
+import (
+	"fmt"
+	"github.com/viant/velty/ast/expr"
+	eexpr "github.com/viant/velty/est/expr"
+	"github.com/viant/velty/est/op"
+	types "github.com/viant/xunsafe/converter"
+	"reflect"
+)
+
+func (p *Planner) compileTernary(actual *expr.Ternary) (*op.Expression, error) {
+	cond, err := p.compileCondition(actual.Cond)
+	if err != nil {
+		return nil, err
+	}
+
+	if cond.Type == nil || cond.Type.Kind() != reflect.Bool {
+		return nil, fmt.Errorf("ternary condition has to be bool, but had %v", cond.Type)
+	}
+
+	x, err := p.compileExpr(actual.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := p.compileExpr(actual.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.Type == nil || y.Type == nil {
+		return nil, fmt.Errorf("unresolved ternary operand")
+	}
+
+	unify, err := types.NormalizeAndUnify(x.Type, y.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	x.Unify = unify.X
+	y.Unify = unify.Y
+	return &op.Expression{
+		Type: unify.RType,
+		New:  eexpr.Ternary(cond, x, y),
+	}, nil
+}
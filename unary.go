@@ -1,13 +1,19 @@
 package velty
 
 import (
+	"github.com/viant/velty/ast"
 	aexpr "github.com/viant/velty/ast/expr"
 	eexpr "github.com/viant/velty/est/expr"
 	"github.com/viant/velty/est/op"
 )
 
 func (p *Planner) compileUnary(actual *aexpr.Unary) (*op.Expression, error) {
-	x, err := p.compileExpr(actual.X)
+	compile := p.compileExpr
+	if actual.Token == ast.NEG {
+		compile = p.compileCondition
+	}
+
+	x, err := compile(actual.X)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +44,15 @@ func AppendInt(dst []byte, i int64, base int) int {
 	return formatBits(dst, uint64(i), base, i < 0)
 }
 
+// AppendUint appends the string form of the unsigned integer i,
+// as generated by FormatUint, to dst and returns the copied size.
+func AppendUint(dst []byte, i uint64, base int) int {
+	if fastSmalls && i < nSmalls && base == 10 {
+		return small(dst, int(i))
+	}
+	return formatBits(dst, i, base, false)
+}
+
 // small returns the string for an i with 0 <= i < nSmalls.
 func small(dst []byte, i int) int {
 	if i < 10 {
@@ -30,3 +30,29 @@ func TestAppendInt(t *testing.T) {
 		assertly.AssertValues(t, testcase.expected, buffer[:coppied])
 	}
 }
+
+func TestAppendUint(t *testing.T) {
+	testcases := []struct {
+		description string
+		value       uint64
+		bufferSize  int
+		expected    []byte
+	}{
+		{
+			value:      7,
+			bufferSize: 5,
+			expected:   []byte{'7'},
+		},
+		{
+			value:      18446744073709551615,
+			bufferSize: 25,
+			expected:   []byte("18446744073709551615"),
+		},
+	}
+
+	for _, testcase := range testcases {
+		buffer := make([]byte, testcase.bufferSize)
+		coppied := AppendUint(buffer, testcase.value, 10)
+		assertly.AssertValues(t, testcase.expected, buffer[:coppied])
+	}
+}